@@ -19,12 +19,25 @@ package v1alpha1
 import (
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 const (
 	// NodeMaintenanceFinalizer is a finalizer for a NodeMaintenance CR deletion
 	PatternFinalizer string = "foregroundDeletePattern"
 	PruneAnnotation  string = "patterns.gitops.hybrid-cloud-patterns.io/prune"
+	// SuspendAnnotation pauses reconciliation of a pattern like spec.suspend
+	SuspendAnnotation string = "patterns.gitops.hybrid-cloud-patterns.io/suspend"
+	// AllowIdentityChangeAnnotation allows edits that change which applications a deployed pattern owns
+	AllowIdentityChangeAnnotation string = "patterns.gitops.hybrid-cloud-patterns.io/allow-identity-change"
+)
+
+// Defaults written into the spec by the mutating webhook
+const (
+	// Git reference deployed when spec.gitSpec.targetRevision is not set
+	DefaultTargetRevision string = "HEAD"
+	// URL to the Validated Patterns Helm chart repo
+	DefaultHelmRepoUrl string = "https://charts.validatedpatterns.io/"
 )
 
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
@@ -38,8 +51,25 @@ type PatternParameter struct {
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,order=1
 	Name string `json:"name"`
 
+	// Value of the parameter. Ignored when ValueFrom is set
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,order=2
-	Value string `json:"value"`
+	Value string `json:"value,omitempty"`
+
+	// Source for the value of the parameter, read from a ConfigMap key in the namespace of the pattern.
+	// The resolved value is passed to Argo as a Helm parameter and never written back to the pattern.
+	// Secret keys are ignored, the Argo Application would hold their value in plain text
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,order=3
+	ValueFrom *PatternParameterSource `json:"valueFrom,omitempty"`
+}
+
+// PatternParameterSource selects the ConfigMap or Secret key holding the value of a parameter.
+// Exactly one of its fields must be set
+type PatternParameterSource struct {
+	// Selects a key of a ConfigMap in the namespace of the pattern
+	ConfigMapKeyRef *v1.ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+
+	// Selects a key of a Secret in the namespace of the pattern
+	SecretKeyRef *v1.SecretKeySelector `json:"secretKeyRef,omitempty"`
 }
 
 // Future fields...
@@ -47,8 +77,6 @@ type PatternParameter struct {
 //   Validation       bool   `json:"validation,omitempty"`
 //   ValidationImage  string `json:"validationImage,omitempty"`
 //   RequiredSecrets []string `json:"requiredSecrets,omitempty"`
-// It would be great to use this, instead of ExtraParameters, but controller-gen barfs on it
-//   Values      map[string]interface{} `json:"values,omitempty" yaml:"valuesLocal,omitempty"`
 
 // PatternSpec defines the desired state of Pattern
 type PatternSpec struct {
@@ -80,15 +108,28 @@ type PatternSpec struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,order=7
 	ExtraValueFiles []string `json:"extraValueFiles,omitempty"`
 
+	// Helm values passed to the clustergroup chart. They override the values files of the pattern, while
+	// the operator managed global parameters and ExtraParameters take precedence over them
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,order=7,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Values *runtime.RawExtension `json:"values,omitempty"`
+
 	// Analytics UUID. Leave empty to autogenerate a random one. Not PII information
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,order=9,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
 	AnalyticsUUID string `json:"analyticsUUID,omitempty"`
-	// Look for external changes every N minutes
-	// ReconcileMinutes int    `json:"reconcileMinutes,omitempty"`
+	// Look for external changes every N minutes. Default: 3
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,order=9,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	// +kubebuilder:validation:Minimum=1
+	ReconcileMinutes int `json:"reconcileMinutes,omitempty"`
 
 	// Comma separated capabilities to enable certain experimental features
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,order=10,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
 	ExperimentalCapabilities string `json:"experimentalCapabilities,omitempty"`
+
+	// Stop making changes to the cluster for this pattern, for example during maintenance windows.
+	// The status of the applications keeps being reported. Can also be set with the suspend annotation
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,order=10,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	Suspend bool `json:"suspend,omitempty"`
 }
 
 type GitConfig struct {
@@ -105,6 +146,12 @@ type GitConfig struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,order=16
 	TargetRevision string `json:"targetRevision,omitempty"`
 
+	// Optional. Full commit SHA from status.revisionHistory to roll the pattern back to. While set it is deployed
+	// instead of TargetRevision. Clear it to resume following TargetRevision
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,order=16,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	// +kubebuilder:validation:Pattern=`^[0-9a-f]{40}$`
+	RollbackRevision string `json:"rollbackRevision,omitempty"`
+
 	// Upstream git repo containing the pattern to deploy. Used when in-cluster fork to point to the upstream pattern repository.
 	// Takes precedence over TargetRepo
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,order=14,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:fieldDependency:gitSpec.inClusterGitServer:true"}
@@ -120,13 +167,30 @@ type GitConfig struct {
 
 	// Optional. K8s secret name where the info for connecting to git can be found. The supported secrets are modeled after the
 	// private repositories in argo (https://argo-cd.readthedocs.io/en/stable/operator-manual/declarative-setup/#repositories)
-	// currently ssh and username+password are supported
+	// currently ssh, username+password (or an access token alone), bearerToken and GitHub Apps are supported, tlsClientCertData
+	// and tlsClientCertKey add a client certificate to the https ones
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,order=18,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
 	TokenSecret string `json:"tokenSecret,omitempty"`
 
 	// Optional. K8s secret namespace where the token for connecting to git can be found
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,order=19,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
 	TokenSecretNamespace string `json:"tokenSecretNamespace,omitempty"`
+
+	// Optional. PEM encoded CA certificates of the git server, read from a ConfigMap or Secret in the namespace
+	// of the pattern. Trusted in addition to the cluster trusted CA bundle
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,order=19,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	CABundleFrom *PatternParameterSource `json:"caBundleFrom,omitempty"`
+
+	// Optional. Skip the verification of the TLS certificate of the git server. Insecure, only meant for test
+	// environments. Default: false
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,order=19,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:booleanSwitch","urn:alm:descriptor:com.tectonic.ui:advanced"}
+	InsecureSkipTLSVerify bool `json:"insecureSkipTLSVerify,omitempty"`
+
+	// Optional. Keys trusted to sign the commit or tag of the target revision, read from a ConfigMap or Secret in
+	// the namespace of the pattern. Holds armored GPG public keys and SSH public keys in authorized_keys or
+	// allowed_signers format. When set, revisions without a valid signature by one of the keys are not deployed
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,order=19,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	SigningKeysFrom *PatternParameterSource `json:"signingKeysFrom,omitempty"`
 }
 
 type MultiSourceConfig struct {
@@ -169,6 +233,65 @@ type PatternApplicationInfo struct {
 	Namespace       string `json:"namespace,omitempty"`
 	AppSyncStatus   string `json:"syncStatus,omitempty"`
 	AppHealthStatus string `json:"healthStatus,omitempty"`
+	// Why the application is not healthy, as reported by Argo
+	// +kubebuilder:validation:MaxLength=512
+	AppHealthMessage string `json:"healthMessage,omitempty"`
+	// Commit the application was last synced to, comma separated for applications with multiple sources
+	SyncedRevision string `json:"syncedRevision,omitempty"`
+	// Time at which the last sync operation finished
+	LastSyncedAt *metav1.Time `json:"lastSyncedAt,omitempty"`
+	// Phase of the last sync operation: Running, Succeeded, Failed, Error or Terminating
+	OperationPhase string `json:"operationPhase,omitempty"`
+	// Message of the last sync operation, usually the reason it failed
+	// +kubebuilder:validation:MaxLength=512
+	OperationMessage string `json:"operationMessage,omitempty"`
+	// Cluster the application deploys to, the server URL or the cluster name
+	DestinationServer string `json:"destinationServer,omitempty"`
+	// Namespace the application deploys to
+	DestinationNamespace string `json:"destinationNamespace,omitempty"`
+	// Number of resources of the application that are out of sync
+	OutOfSyncResources int `json:"outOfSyncResources,omitempty"`
+}
+
+// MaxStatusApplications bounds status.applications. Applications that are not healthy are kept first,
+// status.health always counts all of them
+const MaxStatusApplications = 50
+
+// MaxStatusMessageLength bounds the messages copied from the applications into the status
+const MaxStatusMessageLength = 512
+
+// PatternHealth rolls up the sync and health status of the applications of the pattern
+type PatternHealth struct {
+	// Worst state of the applications, in order Degraded, Missing, OutOfSync, Progressing and Healthy.
+	// Missing when the pattern has no applications yet
+	Status PatternHealthStatus `json:"status"`
+	// Number of applications of the pattern
+	Total int `json:"total"`
+	// Number of applications that are synced and healthy
+	Healthy int `json:"healthy"`
+	// Number of applications that are still progressing or did not report their health yet
+	Progressing int `json:"progressing"`
+	// Number of applications that are degraded
+	Degraded int `json:"degraded"`
+	// Number of applications with missing resources
+	Missing int `json:"missing"`
+	// Number of healthy applications that are out of sync
+	OutOfSync int `json:"outOfSync"`
+}
+
+// PatternRevision describes a git commit of the pattern repository
+type PatternRevision struct {
+	// Commit SHA the target revision resolved to
+	Revision string `json:"revision"`
+	// Author of the commit
+	Author string `json:"author,omitempty"`
+	// First line of the commit message
+	Message string `json:"message,omitempty"`
+	// Time at which the operator first deployed this commit
+	DeployedAt metav1.Time `json:"deployedAt,omitempty"`
+	// Whether the commit or the tag of the target revision is signed by a trusted key. Argo is then pinned to the
+	// commit instead of following the target revision
+	Verified bool `json:"verified,omitempty"`
 }
 
 // PatternStatus defines the observed state of Pattern
@@ -183,6 +306,14 @@ type PatternStatus struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=status
 	LastError string `json:"lastError,omitempty"`
 
+	// Generation of the spec the operator last reconciled completely
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Number of reconcile steps that failed in a row, used to back off retries. Reset on success
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	ConsecutiveFailures int `json:"consecutiveFailures,omitempty"`
+
 	// Number of updates to the pattern
 	// +operator-sdk:csv:customresourcedefinitions:type=status
 	Version int `json:"version,omitempty"`
@@ -199,10 +330,18 @@ type PatternStatus struct {
 	ClusterPlatform string `json:"clusterPlatform,omitempty"`
 	// +operator-sdk:csv:customresourcedefinitions:type=status
 	ClusterVersion string `json:"clusterVersion,omitempty"`
+	// Ready summarizes the per-subsystem conditions. Reasons are machine readable
 	// +operator-sdk:csv:customerresourcedefinitions:type=conditions
-	Conditions []PatternCondition `json:"conditions,omitempty"`
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+	// Applications of the pattern, at most MaxStatusApplications
 	//+operator-sdk:csv:customerresourcedefinitions:type=status
+	// +kubebuilder:validation:MaxItems=50
 	Applications []PatternApplicationInfo `json:"applications,omitempty"`
+	// Overall health of the applications
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	Health *PatternHealth `json:"health,omitempty"`
 	// +operator-sdk:csv:customresourcedefinitions:type=status
 	// +kubebuilder:default:=0
 	AnalyticsSent int `json:"analyticsSent,omitempty"`
@@ -210,6 +349,16 @@ type PatternStatus struct {
 	AnalyticsUUID string `json:"analyticsUUID,omitempty"`
 	// +operator-sdk:csv:customresourcedefinitions:type=status
 	LocalCheckoutPath string `json:"path,omitempty"`
+	// Namespaces the pattern deploys to, as listed in clusterGroup.namespaces
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	Namespaces []string `json:"namespaces,omitempty"`
+	// Commit the target revision currently resolves to
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	Revision *PatternRevision `json:"revision,omitempty"`
+	// Last revisions the pattern was deployed at, most recent first
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +kubebuilder:validation:MaxItems=10
+	RevisionHistory []PatternRevision `json:"revisionHistory,omitempty"`
 	// +operator-sdk:csv:customresourcedefinitions:type=status
 	// DeletionPhase tracks the current phase of pattern deletion
 	// Values: "" (not deleting), "DeleteSpokeChildApps" (Phase 1: Delete child applications from spoke clusters), "DeleteSpoke" (Phase 2: Delete app of apps from spoke),
@@ -222,8 +371,11 @@ type PatternStatus struct {
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:shortName=patt
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Health",type=string,JSONPath=`.status.health.status`
 // +kubebuilder:printcolumn:name="Step",type=string,JSONPath=`.status.lastStep`,priority=1
 // +kubebuilder:printcolumn:name="Error",type=string,JSONPath=`.status.lastError`,priority=2
+// +kubebuilder:printcolumn:name="Revision",type=string,JSONPath=`.status.revision.revision`,priority=1
 // +operator-sdk:csv:customresourcedefinitions:resources={{"Pattern","v1alpha1","patterns"}}
 
 // Pattern is the Schema for the patterns API
@@ -244,22 +396,19 @@ type PatternList struct {
 	Items           []Pattern `json:"items"`
 }
 
-type PatternCondition struct {
-	// Type of deployment condition.
-	Type PatternConditionType `json:"type"`
-	// Status of the condition, one of True, False, Unknown.
-	Status v1.ConditionStatus `json:"status"`
-	// The last time this condition was updated.
-	LastUpdateTime metav1.Time `json:"lastUpdateTime"`
-	// Last time the condition transitioned from one status to another.
-	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
-	// A human readable message indicating details about the transition.
-	Message string `json:"message,omitempty"`
-}
-
 type PatternConditionType string
 
 const (
+	// Ready is True once every subsystem condition below is True
+	Ready PatternConditionType = "Ready"
+	// Subsystem conditions
+	GitOpsOperatorReady PatternConditionType = "GitOpsOperatorReady"
+	ArgoCDReady         PatternConditionType = "ArgoCDReady"
+	GitCheckoutReady    PatternConditionType = "GitCheckoutReady"
+	ApplicationSynced   PatternConditionType = "ApplicationSynced"
+	ApplicationHealthy  PatternConditionType = "ApplicationHealthy"
+	DeletionInProgress  PatternConditionType = "DeletionInProgress"
+
 	GitOutOfSync PatternConditionType = "GitOutOfSync"
 	GitInSync    PatternConditionType = "GitInSync"
 	Synced       PatternConditionType = "Synced"
@@ -269,6 +418,48 @@ const (
 	Progressing  PatternConditionType = "Progressing"
 	Missing      PatternConditionType = "Missing"
 	Suspended    PatternConditionType = "Suspended"
+	RolledBack   PatternConditionType = "RolledBack"
+)
+
+// Reasons of the pattern conditions
+const (
+	// The subsystem is up to date
+	ReasonReconciled = "Reconciled"
+	// The operator changed the subsystem and waits for it to settle
+	ReasonProgressing = "Progressing"
+	// A reconcile step of the subsystem failed, see the message
+	ReasonReconcileFailed = "ReconcileFailed"
+	// A values file the pattern requires is not in the repository
+	ReasonValuesFileMissing = "ValuesFileMissing"
+	// spec.gitSpec.rollbackRevision is not in the revision history
+	ReasonInvalidRollbackRevision = "InvalidRollbackRevision"
+	// The clustergroup application does not exist yet
+	ReasonApplicationMissing = "ApplicationMissing"
+	// Reconciliation was suspended with spec.suspend or the suspend annotation
+	ReasonSuspended = "Suspended"
+	// The pattern is being deleted
+	ReasonDeleting = "Deleting"
+	// The pattern is pinned to spec.gitSpec.rollbackRevision
+	ReasonRollbackPinned = "RollbackPinned"
+	// Argo synced the commit the target revision resolves to
+	ReasonCommitSynced = "CommitSynced"
+	// Argo has not synced the commit the target revision resolves to yet
+	ReasonCommitNotSynced = "CommitNotSynced"
+	// The SSH host key of the git server is unknown or does not match the known hosts
+	ReasonHostKeyVerificationFailed = "HostKeyVerificationFailed"
+	// The commit or tag of the target revision is not signed by a trusted key
+	ReasonSignatureVerificationFailed = "SignatureVerificationFailed"
+)
+
+// +kubebuilder:validation:Enum=Healthy;Progressing;Degraded;Missing;OutOfSync
+type PatternHealthStatus string
+
+const (
+	PatternHealthy     PatternHealthStatus = "Healthy"
+	PatternProgressing PatternHealthStatus = "Progressing"
+	PatternDegraded    PatternHealthStatus = "Degraded"
+	PatternMissing     PatternHealthStatus = "Missing"
+	PatternOutOfSync   PatternHealthStatus = "OutOfSync"
 )
 
 type PatternDeletionPhase string
@@ -0,0 +1,242 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+var (
+	// git@server:foo/bar.git
+	scpLikeGitURL = regexp.MustCompile(`^git@[^\s:/]+:\S+$`)
+	// Abbreviated commit SHAs, which cannot be resolved without the full history
+	shortSHA = regexp.MustCompile(`^[0-9a-f]{7,39}$`)
+	// All-decimal names are common for tags and branches, such as dates
+	decimal = regexp.MustCompile(`^[0-9]+$`)
+	// Helm --set paths: dot separated keys with optional list indexes, dots inside a key escaped as \.
+	helmSetPath = regexp.MustCompile(`^(?:[A-Za-z0-9_/-]|\\\.)+(?:\[[0-9]+\])*(?:\.(?:[A-Za-z0-9_/-]|\\\.)+(?:\[[0-9]+\])*)*$`)
+)
+
+// validatePatternSpec checks the spec of a pattern for mistakes that would otherwise only surface
+// in status.lastError once the operator reconciles it
+func validatePatternSpec(ctx context.Context, cl client.Client, p *Pattern) (admission.Warnings, error) {
+	var warnings admission.Warnings
+	var errs field.ErrorList
+	specPath := field.NewPath("spec")
+	gitPath := specPath.Child("gitSpec")
+	gc := p.Spec.GitConfig
+
+	if gc.TargetRepo == "" && gc.OriginRepo == "" {
+		errs = append(errs, field.Required(gitPath.Child("targetRepo"), "one of targetRepo or originRepo must be set"))
+	}
+	if gc.TargetRepo != "" {
+		errs = append(errs, validateGitRepoURL(gitPath.Child("targetRepo"), gc.TargetRepo)...)
+	}
+	if gc.OriginRepo != "" {
+		errs = append(errs, validateGitRepoURL(gitPath.Child("originRepo"), gc.OriginRepo)...)
+	}
+	if looksLikeShortSHA(gc.TargetRevision) {
+		warnings = append(warnings, fmt.Sprintf("%s %q looks like a short SHA, which is not supported: use the full 40 character commit SHA unless it names a branch or tag",
+			gitPath.Child("targetRevision"), gc.TargetRevision))
+	}
+	if gc.OriginRevision != "" {
+		warnings = append(warnings, "spec.gitSpec.originRevision is deprecated and ignored")
+	}
+	errs = append(errs, validateTokenSecret(ctx, cl, gitPath, gc)...)
+	if gc.CABundleFrom != nil {
+		errs = append(errs, validateParameterSource(gitPath.Child("caBundleFrom"), gc.CABundleFrom)...)
+	}
+	if gc.SigningKeysFrom != nil {
+		errs = append(errs, validateParameterSource(gitPath.Child("signingKeysFrom"), gc.SigningKeysFrom)...)
+	}
+	if gc.InsecureSkipTLSVerify {
+		warnings = append(warnings, "spec.gitSpec.insecureSkipTLSVerify disables the verification of the git server certificate")
+	}
+
+	for i := range p.Spec.ExtraParameters {
+		paramPath := specPath.Child("extraParameters").Index(i)
+		errs = append(errs, validatePatternParameter(paramPath, &p.Spec.ExtraParameters[i])...)
+		if src := p.Spec.ExtraParameters[i].ValueFrom; src != nil && src.SecretKeyRef != nil && src.ConfigMapKeyRef == nil {
+			warnings = append(warnings, fmt.Sprintf("%s is ignored, the Argo CD Application would hold the Secret value in plain text",
+				paramPath.Child("valueFrom", "secretKeyRef")))
+		}
+	}
+	for i, file := range p.Spec.ExtraValueFiles {
+		errs = append(errs, validateValueFilePath(specPath.Child("extraValueFiles").Index(i), file)...)
+	}
+	if p.Spec.Values != nil && len(p.Spec.Values.Raw) > 0 {
+		var values map[string]any
+		if err := json.Unmarshal(p.Spec.Values.Raw, &values); err != nil {
+			errs = append(errs, field.Invalid(specPath.Child("values"), string(p.Spec.Values.Raw), "must be an object of Helm values"))
+		}
+	}
+
+	if len(errs) > 0 {
+		return warnings, apierrors.NewInvalid(GroupVersion.WithKind("Pattern").GroupKind(), p.Name, errs)
+	}
+	return warnings, nil
+}
+
+func validateGitRepoURL(fldPath *field.Path, repoURL string) field.ErrorList {
+	if strings.HasPrefix(repoURL, "git@") {
+		if !scpLikeGitURL.MatchString(repoURL) {
+			return field.ErrorList{field.Invalid(fldPath, repoURL, "ssh repository URLs must look like git@server:foo/bar.git")}
+		}
+		return nil
+	}
+	u, err := url.Parse(repoURL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") {
+		return field.ErrorList{field.Invalid(fldPath, repoURL, "repository URL must be either http/https or start with git@ when using ssh authentication")}
+	}
+	if u.Host == "" || strings.Trim(u.Path, "/") == "" {
+		return field.ErrorList{field.Invalid(fldPath, repoURL, "repository URL must include a server and a repository path")}
+	}
+	return nil
+}
+
+// looksLikeShortSHA only warns: the revision may as well be a branch or tag, which the webhook cannot tell
+func looksLikeShortSHA(revision string) bool {
+	return shortSHA.MatchString(revision) && !decimal.MatchString(revision)
+}
+
+func validateTokenSecret(ctx context.Context, cl client.Client, gitPath *field.Path, gc GitConfig) field.ErrorList {
+	if gc.TokenSecret == "" {
+		return nil
+	}
+	if gc.TokenSecretNamespace == "" {
+		return field.ErrorList{field.Required(gitPath.Child("tokenSecretNamespace"), "must be set when tokenSecret is set")}
+	}
+	secret := &corev1.Secret{}
+	err := cl.Get(ctx, types.NamespacedName{Namespace: gc.TokenSecretNamespace, Name: gc.TokenSecret}, secret)
+	if apierrors.IsNotFound(err) {
+		return field.ErrorList{field.NotFound(gitPath.Child("tokenSecret"), gc.TokenSecretNamespace+"/"+gc.TokenSecret)}
+	} else if err != nil {
+		return field.ErrorList{field.InternalError(gitPath.Child("tokenSecret"), err)}
+	}
+	return nil
+}
+
+func validatePatternParameter(fldPath *field.Path, param *PatternParameter) field.ErrorList {
+	var errs field.ErrorList
+	if !helmSetPath.MatchString(param.Name) {
+		errs = append(errs, field.Invalid(fldPath.Child("name"), param.Name, "must be a valid Helm --set path such as global.something.field"))
+	}
+	if src := param.ValueFrom; src != nil {
+		if param.Value != "" {
+			errs = append(errs, field.Forbidden(fldPath.Child("value"), "may not be set together with valueFrom"))
+		}
+		errs = append(errs, validateParameterSource(fldPath.Child("valueFrom"), src)...)
+	}
+	return errs
+}
+
+func validateParameterSource(fldPath *field.Path, src *PatternParameterSource) field.ErrorList {
+	if src.ConfigMapKeyRef == nil && src.SecretKeyRef == nil {
+		return field.ErrorList{field.Required(fldPath, "one of configMapKeyRef or secretKeyRef must be set")}
+	}
+	if src.ConfigMapKeyRef != nil && src.SecretKeyRef != nil {
+		return field.ErrorList{field.Forbidden(fldPath.Child("secretKeyRef"), "may not be set together with configMapKeyRef")}
+	}
+	return nil
+}
+
+func validateValueFilePath(fldPath *field.Path, file string) field.ErrorList {
+	// Value files are relative to the root of the pattern repository, a leading slash is stripped
+	for _, segment := range strings.Split(path.Clean(strings.TrimLeft(file, "/")), "/") {
+		if segment == ".." {
+			return field.ErrorList{field.Invalid(fldPath, file, "must not reference files outside of the pattern repository")}
+		}
+	}
+	if strings.Contains(file, "://") {
+		return field.ErrorList{field.Invalid(fldPath, file, "must be a path within the pattern repository")}
+	}
+	return nil
+}
+
+// validateIdentityChanges rejects edits to a deployed pattern that change the clustergroup application
+// or the git server it is deployed from, as the operator would leave the old resources behind
+func validateIdentityChanges(old, p *Pattern) error {
+	// Patterns that were never reconciled have nothing to orphan
+	if old.Status.LastStep == "" || strings.EqualFold(p.Annotations[AllowIdentityChangeAnnotation], "true") {
+		return nil
+	}
+	var errs field.ErrorList
+	specPath := field.NewPath("spec")
+
+	if oldGroup, newGroup := effectiveClusterGroupName(old), effectiveClusterGroupName(p); oldGroup != newGroup {
+		fldPath := specPath.Child("clusterGroupName")
+		if p.Spec.Variant != "" || old.Spec.Variant != "" {
+			fldPath = specPath.Child("variant")
+		}
+		errs = append(errs, field.Forbidden(fldPath, fmt.Sprintf(
+			"changing the cluster group from %q to %q renames the clustergroup application from %q to %q, "+
+				"the existing application and everything it deployed would be orphaned",
+			oldGroup, newGroup, old.Name+"-"+oldGroup, p.Name+"-"+newGroup)))
+	}
+
+	if oldGitea, newGitea := usesInClusterGitServer(old), usesInClusterGitServer(p); oldGitea != newGitea {
+		fldPath := specPath.Child("gitSpec", "originRepo")
+		if isInClusterGitServer(old) != isInClusterGitServer(p) {
+			fldPath = specPath.Child("gitSpec", "inClusterGitServer")
+		}
+		msg := "disabling the in-cluster git server would orphan the Gitea instance and its mirror of the pattern repository"
+		if newGitea {
+			msg = "enabling the in-cluster git server moves the pattern to a Gitea mirror, the applications deployed from " +
+				old.Spec.GitConfig.TargetRepo + " would be orphaned"
+		}
+		errs = append(errs, field.Forbidden(fldPath, msg))
+	}
+
+	if len(errs) > 0 {
+		for i := range errs {
+			errs[i].Detail += fmt.Sprintf(". Set the annotation %s=\"true\" to proceed anyway", AllowIdentityChangeAnnotation)
+		}
+		return apierrors.NewInvalid(GroupVersion.WithKind("Pattern").GroupKind(), p.Name, errs)
+	}
+	return nil
+}
+
+// effectiveClusterGroupName mirrors the controller, where spec.variant takes precedence over spec.clusterGroupName
+func effectiveClusterGroupName(p *Pattern) string {
+	if p.Spec.Variant != "" {
+		return p.Spec.Variant
+	}
+	return p.Spec.ClusterGroupName
+}
+
+func isInClusterGitServer(p *Pattern) bool {
+	return p.Spec.GitConfig.InClusterGitServer != nil && *p.Spec.GitConfig.InClusterGitServer
+}
+
+// usesInClusterGitServer mirrors the controller too, which deploys Gitea and mirrors spec.gitSpec.originRepo
+// into it whenever the origin repo is set
+func usesInClusterGitServer(p *Pattern) bool {
+	return isInClusterGitServer(p) || p.Spec.GitConfig.OriginRepo != ""
+}
@@ -0,0 +1,339 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newValidationClient(t *testing.T, objs ...client.Object) client.Client {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add scheme: %v", err)
+	}
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add core scheme: %v", err)
+	}
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+}
+
+func newValidPattern() *Pattern {
+	return &Pattern{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-pattern",
+			Namespace: "default",
+		},
+		Spec: PatternSpec{
+			ClusterGroupName: "hub",
+			GitConfig: GitConfig{
+				TargetRepo:     "https://github.com/example/repo",
+				TargetRevision: "main",
+			},
+		},
+	}
+}
+
+func TestValidatePatternSpec_AcceptsValidPattern(t *testing.T) {
+	cl := newValidationClient(t, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "git-creds", Namespace: "vp"}})
+	p := newValidPattern()
+	p.Spec.GitConfig.TargetRevision = "d0f3fb283cfb17189cba89aa5ff57fd8dcb2a7fd"
+	p.Spec.GitConfig.TokenSecret = "git-creds"
+	p.Spec.GitConfig.TokenSecretNamespace = "vp"
+	p.Spec.ExtraParameters = []PatternParameter{
+		{Name: "global.foo", Value: "bar"},
+		{Name: "clusterGroup.applications[0].name", Value: "app"},
+		{Name: `global.annotations.example\.io/owner`, Value: "team"},
+	}
+	p.Spec.ExtraValueFiles = []string{"/overrides/values-extra.yaml", "overrides/../values-other.yaml"}
+
+	warnings, err := validatePatternSpec(context.Background(), cl, p)
+	if err != nil {
+		t.Errorf("expected no error, got: %v", err)
+	}
+	if warnings != nil {
+		t.Errorf("expected no warnings, got: %v", warnings)
+	}
+}
+
+func TestValidatePatternSpec_Rejects(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(p *Pattern)
+		field  string
+	}{
+		{"missing repo", func(p *Pattern) { p.Spec.GitConfig.TargetRepo = "" }, "spec.gitSpec.targetRepo"},
+		{"repo without scheme", func(p *Pattern) { p.Spec.GitConfig.TargetRepo = "github.com/example/repo" }, "spec.gitSpec.targetRepo"},
+		{"repo without path", func(p *Pattern) { p.Spec.GitConfig.TargetRepo = "https://github.com" }, "spec.gitSpec.targetRepo"},
+		{"malformed ssh repo", func(p *Pattern) { p.Spec.GitConfig.TargetRepo = "git@github.com/example/repo" }, "spec.gitSpec.targetRepo"},
+		{"malformed origin repo", func(p *Pattern) { p.Spec.GitConfig.OriginRepo = "ftp://example.com/repo" }, "spec.gitSpec.originRepo"},
+		{"invalid parameter name", func(p *Pattern) {
+			p.Spec.ExtraParameters = []PatternParameter{{Name: "global.foo=bar", Value: "x"}}
+		}, "spec.extraParameters[0].name"},
+		{"empty parameter path segment", func(p *Pattern) {
+			p.Spec.ExtraParameters = []PatternParameter{{Name: "global..foo", Value: "x"}}
+		}, "spec.extraParameters[0].name"},
+		{"value and valueFrom", func(p *Pattern) {
+			p.Spec.ExtraParameters = []PatternParameter{{Name: "global.foo", Value: "x", ValueFrom: &PatternParameterSource{
+				SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "s"}, Key: "k"},
+			}}}
+		}, "spec.extraParameters[0].value"},
+		{"empty valueFrom", func(p *Pattern) {
+			p.Spec.ExtraParameters = []PatternParameter{{Name: "global.foo", ValueFrom: &PatternParameterSource{}}}
+		}, "spec.extraParameters[0].valueFrom"},
+		{"value file escaping the repo", func(p *Pattern) {
+			p.Spec.ExtraValueFiles = []string{"ok.yaml", "/../../etc/values.yaml"}
+		}, "spec.extraValueFiles[1]"},
+		{"value file URL", func(p *Pattern) {
+			p.Spec.ExtraValueFiles = []string{"https://example.com/values.yaml"}
+		}, "spec.extraValueFiles[0]"},
+		{"token secret without namespace", func(p *Pattern) { p.Spec.GitConfig.TokenSecret = "git-creds" }, "spec.gitSpec.tokenSecretNamespace"},
+		{"missing token secret", func(p *Pattern) {
+			p.Spec.GitConfig.TokenSecret = "missing"
+			p.Spec.GitConfig.TokenSecretNamespace = "vp"
+		}, "spec.gitSpec.tokenSecret"},
+		{"CA bundle from both a ConfigMap and a Secret", func(p *Pattern) {
+			p.Spec.GitConfig.CABundleFrom = &PatternParameterSource{
+				ConfigMapKeyRef: &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "cm"}, Key: "ca.crt"},
+				SecretKeyRef:    &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "s"}, Key: "ca.crt"},
+			}
+		}, "spec.gitSpec.caBundleFrom.secretKeyRef"},
+		{"empty signingKeysFrom", func(p *Pattern) {
+			p.Spec.GitConfig.SigningKeysFrom = &PatternParameterSource{}
+		}, "spec.gitSpec.signingKeysFrom"},
+		{"values that are not an object", func(p *Pattern) {
+			p.Spec.Values = &runtime.RawExtension{Raw: []byte(`["global.foo"]`)}
+		}, "spec.values"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newValidPattern()
+			tt.mutate(p)
+			_, err := validatePatternSpec(context.Background(), newValidationClient(t), p)
+			if err == nil {
+				t.Fatalf("expected an error for %s, got nil", tt.field)
+			}
+			if !strings.Contains(err.Error(), tt.field) {
+				t.Errorf("expected the error to mention %s, got: %v", tt.field, err)
+			}
+		})
+	}
+}
+
+func TestValidatePatternSpec_WarnsAboutOriginRevision(t *testing.T) {
+	p := newValidPattern()
+	p.Spec.GitConfig.OriginRepo = "https://github.com/upstream/repo"
+	p.Spec.GitConfig.OriginRevision = "main"
+
+	warnings, err := validatePatternSpec(context.Background(), newValidationClient(t), p)
+	if err != nil {
+		t.Errorf("expected no error, got: %v", err)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "originRevision") {
+		t.Errorf("expected a warning about originRevision, got: %v", warnings)
+	}
+}
+
+func TestValidatePatternSpec_WarnsAboutShortSHAs(t *testing.T) {
+	p := newValidPattern()
+	p.Spec.GitConfig.TargetRevision = "d0f3fb2"
+
+	warnings, err := validatePatternSpec(context.Background(), newValidationClient(t), p)
+	if err != nil {
+		t.Errorf("expected no error, got: %v", err)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "spec.gitSpec.targetRevision") {
+		t.Errorf("expected a warning about the short SHA, got: %v", warnings)
+	}
+}
+
+func TestValidatePatternSpec_AcceptsNumericTags(t *testing.T) {
+	for _, revision := range []string{"20240101", "1234567"} {
+		p := newValidPattern()
+		p.Spec.GitConfig.TargetRevision = revision
+
+		warnings, err := validatePatternSpec(context.Background(), newValidationClient(t), p)
+		if err != nil {
+			t.Errorf("expected no error for %s, got: %v", revision, err)
+		}
+		if warnings != nil {
+			t.Errorf("expected no warnings for %s, got: %v", revision, warnings)
+		}
+	}
+}
+
+func TestValidatePatternSpec_WarnsAboutInsecureSkipTLSVerify(t *testing.T) {
+	p := newValidPattern()
+	p.Spec.GitConfig.InsecureSkipTLSVerify = true
+
+	warnings, err := validatePatternSpec(context.Background(), newValidationClient(t), p)
+	if err != nil {
+		t.Errorf("expected no error, got: %v", err)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "insecureSkipTLSVerify") {
+		t.Errorf("expected a warning about insecureSkipTLSVerify, got: %v", warnings)
+	}
+}
+
+func TestValidatePatternSpec_WarnsAboutSecretParameters(t *testing.T) {
+	p := newValidPattern()
+	p.Spec.ExtraParameters = []PatternParameter{
+		{Name: "global.domain", ValueFrom: &PatternParameterSource{
+			ConfigMapKeyRef: &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "settings"}, Key: "domain"},
+		}},
+		{Name: "global.token", ValueFrom: &PatternParameterSource{
+			SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "credentials"}, Key: "token"},
+		}},
+	}
+
+	warnings, err := validatePatternSpec(context.Background(), newValidationClient(t), p)
+	if err != nil {
+		t.Errorf("expected no error, got: %v", err)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "spec.extraParameters[1].valueFrom.secretKeyRef") {
+		t.Errorf("expected a warning about the Secret parameter, got: %v", warnings)
+	}
+}
+
+func TestValidateUpdate_SkipsSpecValidationForMetadataChanges(t *testing.T) {
+	validator := &PatternValidator{Client: newValidationClient(t)}
+	oldPattern := newValidPattern()
+	oldPattern.Spec.ExtraValueFiles = []string{"https://example.com/values.yaml"}
+	newPattern := oldPattern.DeepCopy()
+	newPattern.Annotations = map[string]string{PruneAnnotation: "true"}
+
+	if _, err := validator.ValidateUpdate(context.Background(), oldPattern, newPattern); err != nil {
+		t.Errorf("expected no error for a metadata only update, got: %v", err)
+	}
+
+	newPattern.Spec.ClusterGroupName = "factory"
+	if _, err := validator.ValidateUpdate(context.Background(), oldPattern, newPattern); err == nil {
+		t.Error("expected an error when updating the spec of an invalid pattern, got nil")
+	}
+}
+
+func TestValidateUpdate_RejectsIdentityChanges(t *testing.T) {
+	enabled := true
+	tests := []struct {
+		name     string
+		mutate   func(p *Pattern)
+		contains string
+	}{
+		{
+			name:     "cluster group renamed",
+			mutate:   func(p *Pattern) { p.Spec.ClusterGroupName = "factory" },
+			contains: `"test-pattern-hub" to "test-pattern-factory"`,
+		},
+		{
+			name:     "variant overrides the cluster group",
+			mutate:   func(p *Pattern) { p.Spec.Variant = "factory" },
+			contains: "spec.variant",
+		},
+		{
+			name:     "in-cluster git server enabled",
+			mutate:   func(p *Pattern) { p.Spec.GitConfig.InClusterGitServer = &enabled },
+			contains: "https://github.com/example/repo would be orphaned",
+		},
+	}
+
+	validator := &PatternValidator{Client: newValidationClient(t)}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldPattern := newValidPattern()
+			oldPattern.Status.LastStep = "reconcile complete"
+			newPattern := oldPattern.DeepCopy()
+			tt.mutate(newPattern)
+
+			_, err := validator.ValidateUpdate(context.Background(), oldPattern, newPattern)
+			if err == nil {
+				t.Fatal("expected an error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.contains) || !strings.Contains(err.Error(), AllowIdentityChangeAnnotation) {
+				t.Errorf("expected error to contain %q and the override annotation, got: %v", tt.contains, err)
+			}
+
+			newPattern.Annotations = map[string]string{AllowIdentityChangeAnnotation: "true"}
+			if _, err := validator.ValidateUpdate(context.Background(), oldPattern, newPattern); err != nil {
+				t.Errorf("expected the override annotation to allow the change, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateUpdate_RejectsOriginRepoChanges(t *testing.T) {
+	validator := &PatternValidator{Client: newValidationClient(t)}
+	withoutOrigin := newValidPattern()
+	withoutOrigin.Status.LastStep = "reconcile complete"
+	withOrigin := withoutOrigin.DeepCopy()
+	withOrigin.Spec.GitConfig.OriginRepo = "https://github.com/example/upstream"
+
+	for name, change := range map[string]struct {
+		old, new *Pattern
+		contains string
+	}{
+		"origin repo set":   {withoutOrigin, withOrigin, "https://github.com/example/repo would be orphaned"},
+		"origin repo unset": {withOrigin, withoutOrigin, "orphan the Gitea instance"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := validator.ValidateUpdate(context.Background(), change.old, change.new)
+			if err == nil {
+				t.Fatal("expected an error, got nil")
+			}
+			if !strings.Contains(err.Error(), "spec.gitSpec.originRepo") || !strings.Contains(err.Error(), change.contains) {
+				t.Errorf("expected error on spec.gitSpec.originRepo to contain %q, got: %v", change.contains, err)
+			}
+		})
+	}
+
+	// Pointing the mirror at another upstream keeps the pattern on the in-cluster git server
+	moved := withOrigin.DeepCopy()
+	moved.Spec.GitConfig.OriginRepo = "https://github.com/example/other-upstream"
+	if _, err := validator.ValidateUpdate(context.Background(), withOrigin, moved); err != nil {
+		t.Errorf("expected no error when the origin repo changes, got: %v", err)
+	}
+}
+
+func TestValidateUpdate_AllowsIdentityChanges(t *testing.T) {
+	disabled := false
+	validator := &PatternValidator{Client: newValidationClient(t)}
+
+	// Nothing was deployed yet
+	oldPattern := newValidPattern()
+	newPattern := oldPattern.DeepCopy()
+	newPattern.Spec.ClusterGroupName = "factory"
+	if _, err := validator.ValidateUpdate(context.Background(), oldPattern, newPattern); err != nil {
+		t.Errorf("expected no error for a pattern that was never reconciled, got: %v", err)
+	}
+
+	// Same effective cluster group and git server
+	oldPattern.Status.LastStep = "reconcile complete"
+	newPattern = oldPattern.DeepCopy()
+	newPattern.Spec.Variant = "hub"
+	newPattern.Spec.GitConfig.InClusterGitServer = &disabled
+	if _, err := validator.ValidateUpdate(context.Background(), oldPattern, newPattern); err != nil {
+		t.Errorf("expected no error when the identity does not change, got: %v", err)
+	}
+}
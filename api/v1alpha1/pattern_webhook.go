@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"strings"
 
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -37,6 +38,9 @@ var patternlog = logf.Log.WithName("pattern-resource")
 // PatternValidator validates Pattern resources to enforce singleton semantics.
 type PatternValidator struct {
 	Client client.Client
+	// AllowMultiplePatterns reports whether the cluster opted in to running more than one Pattern.
+	// The singleton rule is enforced when it is nil
+	AllowMultiplePatterns func(ctx context.Context, cl client.Client) bool
 }
 
 //nolint:lll
@@ -44,15 +48,57 @@ type PatternValidator struct {
 
 var _ webhook.CustomValidator = &PatternValidator{}
 
+// +kubebuilder:object:generate=false
+// +k8s:deepcopy-gen=false
+// +k8s:openapi-gen=false
+// PatternDefaulter persists the stable spec defaults so that the stored Pattern shows what the operator deploys.
+// Values derived from the cluster or from other fields are left to the controller
+type PatternDefaulter struct{}
+
+//nolint:lll
+// +kubebuilder:webhook:verbs=create;update,path=/mutate-gitops-hybrid-cloud-patterns-io-v1alpha1-pattern,mutating=true,failurePolicy=fail,groups=gitops.hybrid-cloud-patterns.io,resources=patterns,versions=v1alpha1,name=mpattern.gitops.hybrid-cloud-patterns.io,admissionReviewVersions=v1,sideEffects=none
+
+var _ webhook.CustomDefaulter = &PatternDefaulter{}
+
 // SetupWebhookWithManager will setup the manager to manage the webhooks
 func (r *PatternValidator) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	r.Client = mgr.GetClient()
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(&Pattern{}).
 		WithValidator(r).
+		WithDefaulter(&PatternDefaulter{}).
 		Complete()
 }
 
+// Default implements webhook.CustomDefaulter so a webhook will be registered for the type
+func (d *PatternDefaulter) Default(_ context.Context, obj runtime.Object) error {
+	p, err := convertToPattern(obj)
+	if err != nil {
+		return err
+	}
+	patternlog.Info("default", "name", p.Name)
+	setPatternSpecDefaults(&p.Spec)
+	return nil
+}
+
+// setPatternSpecDefaults fills in the defaults that do not depend on the cluster. The git hostname
+// and the clusterGroupName alias are not persisted because they follow targetRepo and variant
+func setPatternSpecDefaults(spec *PatternSpec) {
+	if spec.GitConfig.TargetRevision == "" {
+		spec.GitConfig.TargetRevision = DefaultTargetRevision
+	}
+	if spec.MultiSourceConfig.Enabled == nil {
+		enabled := true
+		spec.MultiSourceConfig.Enabled = &enabled
+	}
+	if spec.MultiSourceConfig.HelmRepoUrl == "" {
+		spec.MultiSourceConfig.HelmRepoUrl = DefaultHelmRepoUrl
+	}
+	if spec.GitOpsConfig == nil {
+		spec.GitOpsConfig = &GitOpsConfig{}
+	}
+}
+
 // ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type
 func (r *PatternValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
 	p, err := convertToPattern(obj)
@@ -66,23 +112,43 @@ func (r *PatternValidator) ValidateCreate(ctx context.Context, obj runtime.Objec
 		return nil, err
 	}
 
+	warnings, err := validatePatternSpec(ctx, r.Client, p)
+	if err != nil {
+		patternlog.Error(err, "validate create failed", "name", p.Name)
+		return warnings, err
+	}
+
 	var patterns PatternList
 	if err = r.Client.List(ctx, &patterns); err != nil {
 		return nil, fmt.Errorf("failed to list Pattern resources: %v", err)
 	}
-	if len(patterns.Items) > 0 {
-		return nil, fmt.Errorf("only one Pattern resource is allowed")
+	if len(patterns.Items) == 0 {
+		return warnings, nil
+	}
+	if r.AllowMultiplePatterns == nil || !r.AllowMultiplePatterns(ctx, r.Client) {
+		return warnings, fmt.Errorf("only one Pattern resource is allowed unless patterns.allowMultiple is set to \"true\" in the patterns-operator-config ConfigMap")
+	}
+	// The Argo CD applications and the resources labelled after a pattern only carry its name
+	for i := range patterns.Items {
+		if patterns.Items[i].Name == p.Name {
+			return warnings, fmt.Errorf("a Pattern named %q already exists in the %q namespace, pattern names must be unique in the cluster",
+				p.Name, patterns.Items[i].Namespace)
+		}
 	}
 
-	return nil, nil
+	return warnings, nil
 }
 
 // ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type
-func (r *PatternValidator) ValidateUpdate(_ context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+func (r *PatternValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
 	p, err := convertToPattern(newObj)
 	if err != nil {
 		return nil, err
 	}
+	old, err := convertToPattern(oldObj)
+	if err != nil {
+		return nil, err
+	}
 	patternlog.Info("validate update", "name", p.Name)
 
 	if err := validateVariantAlias(p); err != nil {
@@ -90,7 +156,23 @@ func (r *PatternValidator) ValidateUpdate(_ context.Context, _, newObj runtime.O
 		return nil, err
 	}
 
-	return nil, nil
+	// Metadata only updates, such as adding the prune annotation, must keep working on patterns
+	// created before the spec was validated or defaulted
+	oldSpec, newSpec := old.Spec.DeepCopy(), p.Spec.DeepCopy()
+	setPatternSpecDefaults(oldSpec)
+	setPatternSpecDefaults(newSpec)
+	if equality.Semantic.DeepEqual(oldSpec, newSpec) {
+		return nil, nil
+	}
+	if err := validateIdentityChanges(old, p); err != nil {
+		patternlog.Error(err, "validate update failed", "name", p.Name)
+		return nil, err
+	}
+	warnings, err := validatePatternSpec(ctx, r.Client, p)
+	if err != nil {
+		patternlog.Error(err, "validate update failed", "name", p.Name)
+	}
+	return warnings, err
 }
 
 // ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type
@@ -18,10 +18,12 @@ package v1alpha1
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
@@ -100,6 +102,99 @@ func TestValidateCreate_DeniesSecondPattern(t *testing.T) {
 	}
 }
 
+func TestValidateCreate_AllowsSecondPatternWhenOptedIn(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add scheme: %v", err)
+	}
+
+	existing := &Pattern{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "platform",
+			Namespace: "default",
+		},
+		Spec: PatternSpec{
+			ClusterGroupName: "hub",
+		},
+	}
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(existing).Build()
+	for _, allow := range []bool{true, false} {
+		validator := &PatternValidator{
+			Client: fakeClient,
+			AllowMultiplePatterns: func(_ context.Context, _ client.Client) bool {
+				return allow
+			},
+		}
+
+		p := &Pattern{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "line-of-business",
+				Namespace: "default",
+			},
+			Spec: PatternSpec{
+				ClusterGroupName: "hub",
+				GitConfig: GitConfig{
+					TargetRepo: "https://github.com/example/repo2",
+				},
+			},
+		}
+
+		_, err := validator.ValidateCreate(context.Background(), p)
+		if allow && err != nil {
+			t.Errorf("expected no error for second pattern when opted in, got: %v", err)
+		}
+		if !allow && err == nil {
+			t.Error("expected error for second pattern when not opted in, got nil")
+		}
+	}
+}
+
+func TestValidateCreate_DeniesSameNameInAnotherNamespace(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add scheme: %v", err)
+	}
+
+	existing := &Pattern{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "platform",
+			Namespace: "default",
+		},
+		Spec: PatternSpec{
+			ClusterGroupName: "hub",
+		},
+	}
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(existing).Build()
+	validator := &PatternValidator{
+		Client: fakeClient,
+		AllowMultiplePatterns: func(_ context.Context, _ client.Client) bool {
+			return true
+		},
+	}
+
+	p := &Pattern{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "platform",
+			Namespace: "team-a",
+		},
+		Spec: PatternSpec{
+			ClusterGroupName: "hub",
+			GitConfig: GitConfig{
+				TargetRepo: "https://github.com/example/repo2",
+			},
+		},
+	}
+
+	_, err := validator.ValidateCreate(context.Background(), p)
+	if err == nil {
+		t.Error("expected error when creating a pattern named like one in another namespace, got nil")
+	} else if !strings.Contains(err.Error(), `"default"`) {
+		t.Errorf("expected the error to name the namespace of the existing pattern, got: %v", err)
+	}
+}
+
 func TestValidateCreate_AllowsVariantOnly(t *testing.T) {
 	scheme := runtime.NewScheme()
 	if err := AddToScheme(scheme); err != nil {
@@ -316,3 +411,115 @@ func TestValidateDelete_DeniesWithoutPruneAnnotation(t *testing.T) {
 		t.Error("expected error on delete without prune annotation, got nil")
 	}
 }
+
+func TestDefault_FillsStableDefaults(t *testing.T) {
+	p := &Pattern{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-pattern",
+			Namespace: "default",
+		},
+		Spec: PatternSpec{
+			Variant: "hub",
+			GitConfig: GitConfig{
+				TargetRepo: "https://github.com/example/repo",
+			},
+		},
+	}
+
+	if err := (&PatternDefaulter{}).Default(context.Background(), p); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if p.Spec.GitConfig.TargetRevision != DefaultTargetRevision {
+		t.Errorf("expected targetRevision %q, got %q", DefaultTargetRevision, p.Spec.GitConfig.TargetRevision)
+	}
+	if p.Spec.MultiSourceConfig.Enabled == nil || !*p.Spec.MultiSourceConfig.Enabled {
+		t.Errorf("expected multiSourceConfig.enabled to default to true, got %v", p.Spec.MultiSourceConfig.Enabled)
+	}
+	if p.Spec.MultiSourceConfig.HelmRepoUrl != DefaultHelmRepoUrl {
+		t.Errorf("expected helmRepoUrl %q, got %q", DefaultHelmRepoUrl, p.Spec.MultiSourceConfig.HelmRepoUrl)
+	}
+	if p.Spec.GitOpsConfig == nil {
+		t.Error("expected gitOpsSpec to be set")
+	}
+	// Values that follow other fields are left to the controller
+	if p.Spec.GitConfig.Hostname != "" {
+		t.Errorf("expected hostname to stay empty, got %q", p.Spec.GitConfig.Hostname)
+	}
+	if p.Spec.ClusterGroupName != "" {
+		t.Errorf("expected clusterGroupName to stay empty, got %q", p.Spec.ClusterGroupName)
+	}
+}
+
+func TestDefault_KeepsUserValues(t *testing.T) {
+	disabled := false
+	p := &Pattern{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-pattern",
+			Namespace: "default",
+		},
+		Spec: PatternSpec{
+			ClusterGroupName: "hub",
+			GitConfig: GitConfig{
+				TargetRepo:     "https://github.com/example/repo",
+				TargetRevision: "main",
+			},
+			MultiSourceConfig: MultiSourceConfig{
+				Enabled:     &disabled,
+				HelmRepoUrl: "https://charts.example.com/",
+			},
+			GitOpsConfig: &GitOpsConfig{ManualSync: true},
+		},
+	}
+
+	if err := (&PatternDefaulter{}).Default(context.Background(), p); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if p.Spec.GitConfig.TargetRevision != "main" {
+		t.Errorf("expected targetRevision to be kept, got %q", p.Spec.GitConfig.TargetRevision)
+	}
+	if *p.Spec.MultiSourceConfig.Enabled {
+		t.Error("expected multiSourceConfig.enabled to stay false")
+	}
+	if p.Spec.MultiSourceConfig.HelmRepoUrl != "https://charts.example.com/" {
+		t.Errorf("expected helmRepoUrl to be kept, got %q", p.Spec.MultiSourceConfig.HelmRepoUrl)
+	}
+	if !p.Spec.GitOpsConfig.ManualSync {
+		t.Error("expected gitOpsSpec.manualSync to be kept")
+	}
+}
+
+func TestDefault_RejectsNonPatternObject(t *testing.T) {
+	if err := (&PatternDefaulter{}).Default(context.Background(), &PatternList{}); err == nil {
+		t.Error("expected error for non-Pattern object, got nil")
+	}
+}
+
+func TestValidateUpdate_SkipsSpecValidationWhenOnlyDefaultsAdded(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to add scheme: %v", err)
+	}
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	validator := &PatternValidator{Client: fakeClient}
+
+	// A pattern stored before validation existed, without a repository
+	old := &Pattern{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-pattern",
+			Namespace: "default",
+		},
+		Spec: PatternSpec{
+			Variant: "hub",
+		},
+	}
+	updated := old.DeepCopy()
+	updated.Annotations = map[string]string{PruneAnnotation: "true"}
+	if err := (&PatternDefaulter{}).Default(context.Background(), updated); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if _, err := validator.ValidateUpdate(context.Background(), old, updated); err != nil {
+		t.Errorf("expected no error on metadata only update, got: %v", err)
+	}
+}
@@ -21,6 +21,8 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -32,6 +34,16 @@ func (in *GitConfig) DeepCopyInto(out *GitConfig) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.CABundleFrom != nil {
+		in, out := &in.CABundleFrom, &out.CABundleFrom
+		*out = new(PatternParameterSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SigningKeysFrom != nil {
+		in, out := &in.SigningKeysFrom, &out.SigningKeysFrom
+		*out = new(PatternParameterSource)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitConfig.
@@ -109,6 +121,10 @@ func (in *Pattern) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PatternApplicationInfo) DeepCopyInto(out *PatternApplicationInfo) {
 	*out = *in
+	if in.LastSyncedAt != nil {
+		in, out := &in.LastSyncedAt, &out.LastSyncedAt
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatternApplicationInfo.
@@ -122,18 +138,16 @@ func (in *PatternApplicationInfo) DeepCopy() *PatternApplicationInfo {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PatternCondition) DeepCopyInto(out *PatternCondition) {
+func (in *PatternHealth) DeepCopyInto(out *PatternHealth) {
 	*out = *in
-	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
-	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatternCondition.
-func (in *PatternCondition) DeepCopy() *PatternCondition {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatternHealth.
+func (in *PatternHealth) DeepCopy() *PatternHealth {
 	if in == nil {
 		return nil
 	}
-	out := new(PatternCondition)
+	out := new(PatternHealth)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -173,6 +187,11 @@ func (in *PatternList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PatternParameter) DeepCopyInto(out *PatternParameter) {
 	*out = *in
+	if in.ValueFrom != nil {
+		in, out := &in.ValueFrom, &out.ValueFrom
+		*out = new(PatternParameterSource)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatternParameter.
@@ -185,6 +204,47 @@ func (in *PatternParameter) DeepCopy() *PatternParameter {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PatternParameterSource) DeepCopyInto(out *PatternParameterSource) {
+	*out = *in
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(v1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatternParameterSource.
+func (in *PatternParameterSource) DeepCopy() *PatternParameterSource {
+	if in == nil {
+		return nil
+	}
+	out := new(PatternParameterSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PatternRevision) DeepCopyInto(out *PatternRevision) {
+	*out = *in
+	in.DeployedAt.DeepCopyInto(&out.DeployedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatternRevision.
+func (in *PatternRevision) DeepCopy() *PatternRevision {
+	if in == nil {
+		return nil
+	}
+	out := new(PatternRevision)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PatternSpec) DeepCopyInto(out *PatternSpec) {
 	*out = *in
@@ -198,13 +258,20 @@ func (in *PatternSpec) DeepCopyInto(out *PatternSpec) {
 	if in.ExtraParameters != nil {
 		in, out := &in.ExtraParameters, &out.ExtraParameters
 		*out = make([]PatternParameter, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.ExtraValueFiles != nil {
 		in, out := &in.ExtraValueFiles, &out.ExtraValueFiles
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatternSpec.
@@ -222,7 +289,7 @@ func (in *PatternStatus) DeepCopyInto(out *PatternStatus) {
 	*out = *in
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make([]PatternCondition, len(*in))
+		*out = make([]metav1.Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -230,8 +297,32 @@ func (in *PatternStatus) DeepCopyInto(out *PatternStatus) {
 	if in.Applications != nil {
 		in, out := &in.Applications, &out.Applications
 		*out = make([]PatternApplicationInfo, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Health != nil {
+		in, out := &in.Health, &out.Health
+		*out = new(PatternHealth)
+		**out = **in
+	}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Revision != nil {
+		in, out := &in.Revision, &out.Revision
+		*out = new(PatternRevision)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RevisionHistory != nil {
+		in, out := &in.RevisionHistory, &out.RevisionHistory
+		*out = make([]PatternRevision, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatternStatus.
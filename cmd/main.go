@@ -30,13 +30,16 @@ import (
 
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	corev1 "k8s.io/api/core/v1"
 	k8sruntime "k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
 	argov1beta1api "github.com/argoproj-labs/argocd-operator/api/v1beta1"
@@ -44,6 +47,7 @@ import (
 	controllers "github.com/hybrid-cloud-patterns/patterns-operator/internal/controller"
 	"github.com/hybrid-cloud-patterns/patterns-operator/internal/controller/console"
 	"github.com/hybrid-cloud-patterns/patterns-operator/version"
+	configv1 "github.com/openshift/api/config/v1"
 	consolev1 "github.com/openshift/api/console/v1"
 	operatorv1 "github.com/openshift/api/operator/v1"
 	//+kubebuilder:scaffold:imports
@@ -58,6 +62,7 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
 	utilruntime.Must(gitopsv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(configv1.AddToScheme(scheme))
 	utilruntime.Must(consolev1.AddToScheme(scheme))
 	utilruntime.Must(operatorv1.AddToScheme(scheme))
 	//+kubebuilder:scaffold:scheme
@@ -66,6 +71,13 @@ func init() {
 func main() {
 	var enableLeaderElection bool
 	var probeAddr string
+	var metricsAddr string
+	var secureMetrics bool
+	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8443", "The address the metrics endpoint binds to. "+
+		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
+	flag.BoolVar(&secureMetrics, "metrics-secure", true,
+		"If set, the metrics endpoint is served securely via HTTPS and requires an authorized token. "+
+			"Use --metrics-secure=false to use HTTP instead.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
@@ -82,13 +94,27 @@ func main() {
 
 	setupLog.Info("detected operator namespace", "namespace", controllers.DetectOperatorNamespace())
 
+	metricsServerOptions := server.Options{
+		BindAddress:   metricsAddr,
+		SecureServing: secureMetrics,
+	}
+	if secureMetrics {
+		// Only allow scrapes from clients that are authorized to get /metrics
+		metricsServerOptions.FilterProvider = filters.WithAuthenticationAndAuthorization
+	}
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:                 scheme,
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
 		LeaderElectionID:       "f2850479.hybrid-cloud-patterns.io",
-		Metrics: server.Options{
-			BindAddress: "0",
+		Metrics:                metricsServerOptions,
+		// The controller watches the ConfigMaps and Secrets of the whole cluster for their metadata only,
+		// reading them through the cache would keep a copy of every one of them in memory
+		Client: client.Options{
+			Cache: &client.CacheOptions{
+				DisableFor: []client.Object{&corev1.ConfigMap{}, &corev1.Secret{}},
+			},
 		},
 		//LeaderElectionNamespace: "default", // Use this if we ever want to enforce a single instance per cluster
 	})
@@ -100,11 +126,19 @@ func main() {
 	registerComponentOrExit(mgr, argov1beta1api.AddToScheme)
 
 	if err := mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
-		if err := console.CreateOrUpdatePlugin(ctx, mgr.GetClient()); err != nil {
-			setupLog.Error(err, "unable to create/update console plugin")
+		// The console plugin and the catalog only exist on OpenShift, try them anyway when detection fails
+		openShift, err := controllers.IsOpenShiftCluster(mgr.GetConfig())
+		if err != nil {
+			setupLog.Error(err, "unable to detect the cluster platform")
+			openShift = true
 		}
-		if err := console.EnablePlugin(ctx, mgr.GetClient()); err != nil {
-			setupLog.Error(err, "unable to enable console plugin")
+		if openShift {
+			if err := console.CreateOrUpdatePlugin(ctx, mgr.GetClient()); err != nil {
+				setupLog.Error(err, "unable to create/update console plugin")
+			}
+			if err := console.EnablePlugin(ctx, mgr.GetClient()); err != nil {
+				setupLog.Error(err, "unable to enable console plugin")
+			}
 		}
 		cm, err := controllers.GetPatternsOperatorConfigMap(ctx, mgr.GetClient())
 		if err != nil {
@@ -116,8 +150,10 @@ func main() {
 				setupLog.Error(err, "unable to create operator configmap")
 			}
 		}
-		if err := console.CreateOrUpdateCatalog(ctx, mgr.GetClient(), cm); err != nil {
-			setupLog.Error(err, "unable to create/update catalog deployment")
+		if openShift {
+			if err := console.CreateOrUpdateCatalog(ctx, mgr.GetClient(), cm); err != nil {
+				setupLog.Error(err, "unable to create/update catalog deployment")
+			}
 		}
 		return nil
 	})); err != nil {
@@ -130,12 +166,14 @@ func main() {
 		Client:          mgr.GetClient(),
 		Scheme:          mgr.GetScheme(),
 		AnalyticsClient: controllers.AnalyticsInit(!analyticsEnabled, setupLog),
+		Recorder:        mgr.GetEventRecorderFor("patterns-operator"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Pattern")
 		os.Exit(1)
 	}
 	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
-		if err = (&gitopsv1alpha1.PatternValidator{}).SetupWebhookWithManager(mgr); err != nil {
+		validator := &gitopsv1alpha1.PatternValidator{AllowMultiplePatterns: controllers.AllowMultiplePatterns}
+		if err = validator.SetupWebhookWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "Pattern")
 			os.Exit(1)
 		}
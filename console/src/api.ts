@@ -544,6 +544,32 @@ export interface PatternApplicationInfo {
   syncStatus: string;
   healthStatus: string;
   healthMessage?: string;
+  syncedRevision?: string;
+  lastSyncedAt?: string;
+  operationPhase?: string;
+  operationMessage?: string;
+  destinationServer?: string;
+  destinationNamespace?: string;
+  outOfSyncResources?: number;
+}
+
+export interface PatternCondition {
+  type: string;
+  status: 'True' | 'False' | 'Unknown';
+  reason: string;
+  message?: string;
+  observedGeneration?: number;
+  lastTransitionTime: string;
+}
+
+export interface PatternHealth {
+  status: 'Healthy' | 'Progressing' | 'Degraded' | 'Missing' | 'OutOfSync';
+  total: number;
+  healthy: number;
+  progressing: number;
+  degraded: number;
+  missing: number;
+  outOfSync: number;
 }
 
 export interface PatternCRStatus {
@@ -551,8 +577,9 @@ export interface PatternCRStatus {
   lastStep?: string;
   lastError?: string;
   deletionPhase?: string;
-  conditions?: any[];
+  conditions?: PatternCondition[];
   applications?: PatternApplicationInfo[];
+  health?: PatternHealth;
   version?: number;
 }
 
@@ -576,6 +603,7 @@ export async function fetchPatternCR(name: string): Promise<PatternCRStatus> {
       deletionPhase: status.deletionPhase,
       conditions: status.conditions,
       applications: status.applications,
+      health: status.health,
       version: status.version,
     };
   } catch (err) {
@@ -169,7 +169,11 @@ func (v *VpAnalytics) SendPatternInstallationInfo(p *api.Pattern) bool {
 		properties.Set(k, v)
 	}
 	properties.Set("pattern", p.Name)
-	client := analytics.New(v.apiKey)
+	client, err := newAnalyticsClient(v.apiKey)
+	if err != nil {
+		v.logger.Info("Creating the analytics client failed:", "info", err)
+		return false
+	}
 	defer client.Close()
 	id := analytics.Identify{
 		UserId:  getNewUUID(p),
@@ -183,9 +187,10 @@ func (v *VpAnalytics) SendPatternInstallationInfo(p *api.Pattern) bool {
 			Set("repobasename", getBaseGitRepo(p)).
 			Set("pattern", p.Name),
 	}
-	err := retryAnalytics(v.logger, 2, 1, id, client.Enqueue)
+	err = retryAnalytics(v.logger, 2, 1, id, client.Enqueue)
 	if err != nil {
 		v.logger.Info("Sending Installation info failed:", "info", err)
+		analyticsFailures.WithLabelValues("identify").Inc()
 		return false
 	}
 	v.logger.Info("Sent Identify Event")
@@ -200,11 +205,16 @@ func (v *VpAnalytics) SendPatternStartEventInfo(p *api.Pattern) bool {
 		return false
 	}
 
-	client := analytics.New(v.apiKey)
+	client, err := newAnalyticsClient(v.apiKey)
+	if err != nil {
+		v.logger.Info("Creating the analytics client failed:", "info", err)
+		return false
+	}
 	defer client.Close()
-	err := retryAnalytics(v.logger, 2, 1, getAnalyticsTrack(p, PatternStartEvent), client.Enqueue)
+	err = retryAnalytics(v.logger, 2, 1, getAnalyticsTrack(p, PatternStartEvent), client.Enqueue)
 	if err != nil {
 		v.logger.Info("Sending update info failed:", "info", err)
+		analyticsFailures.WithLabelValues(PatternStartEvent).Inc()
 		return false
 	}
 	v.logger.Info("Sent an update Info event:", "event", PatternStartEvent)
@@ -221,7 +231,11 @@ func (v *VpAnalytics) SendPatternEndEventInfo(p *api.Pattern) bool {
 		return false
 	}
 	var event string
-	client := analytics.New(v.apiKey)
+	client, err := newAnalyticsClient(v.apiKey)
+	if err != nil {
+		v.logger.Info("Creating the analytics client failed:", "info", err)
+		return false
+	}
 	defer client.Close()
 
 	// If we already sent the end event once, let's now call it refresh event from now on
@@ -230,9 +244,10 @@ func (v *VpAnalytics) SendPatternEndEventInfo(p *api.Pattern) bool {
 	} else {
 		event = PatternEndEvent
 	}
-	err := retryAnalytics(v.logger, 2, 1, getAnalyticsTrack(p, event), client.Enqueue)
+	err = retryAnalytics(v.logger, 2, 1, getAnalyticsTrack(p, event), client.Enqueue)
 	if err != nil {
 		v.logger.Info("Sending update info failed:", "info", err)
+		analyticsFailures.WithLabelValues(event).Inc()
 		return false
 	}
 	v.logger.Info("Sent an update Info event:", "event", event)
@@ -245,6 +260,11 @@ func (v *VpAnalytics) SendPatternEndEventInfo(p *api.Pattern) bool {
 	return true
 }
 
+// newAnalyticsClient sends the events through the cluster proxy, which also covers a TLS intercepting one
+func newAnalyticsClient(apiKey string) (analytics.Client, error) {
+	return analytics.NewWithConfig(apiKey, analytics.Config{Transport: getHTTPSTransport(nil)})
+}
+
 func retryAnalytics(logger logr.Logger, attempts int, sleep time.Duration, m analytics.Message, f func(analytics.Message) error) (err error) {
 	for i := 0; i < attempts; i++ {
 		err = f(m)
@@ -18,6 +18,7 @@ package controllers
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"os"
@@ -26,6 +27,7 @@ import (
 	"strings"
 
 	"dario.cat/mergo"
+	"helm.sh/helm/v3/pkg/chartutil"
 	v1 "k8s.io/api/core/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -54,6 +56,8 @@ const (
 	ArgoCDAPIVersion = ArgoCDGroup + "/" + ArgoCDVersion
 
 	ApplicationKind = "Application"
+	// Label set on the applications of a pattern, the value is the name of the pattern
+	PatternApplicationLabel = "validatedpatterns.io/pattern"
 
 	DefaultProject       = "default"
 	InClusterDestination = "in-cluster"
@@ -576,14 +580,14 @@ func haveArgo(client dynamic.Interface, name, namespace string) bool {
 	return err == nil
 }
 
-func createOrUpdateArgoCD(client dynamic.Interface, fullClient kubernetes.Interface, name, namespace string, patternsOperatorConfig PatternsOperatorConfig) error {
+func createOrUpdateArgoCD(client dynamic.Interface, fullClient kubernetes.Interface, name, namespace string, patternsOperatorConfig PatternsOperatorConfig) (bool, error) {
 	argo := newArgoCD(name, namespace, patternsOperatorConfig)
 	gvr := schema.GroupVersionResource{Group: ArgoCDGroup, Version: ArgoCDVersion, Resource: ArgoCDResource}
 
 	// we skip this check if fullClient is explicitly nil for simpler testing
 	if fullClient != nil {
 		if err := checkAPIVersion(fullClient, ArgoCDGroup, ArgoCDVersion); err != nil {
-			return fmt.Errorf("cannot find a sufficiently recent argocd crd version: %v", err)
+			return false, fmt.Errorf("cannot find a sufficiently recent argocd crd version: %v", err)
 		}
 	}
 
@@ -596,7 +600,7 @@ func createOrUpdateArgoCD(client dynamic.Interface, fullClient kubernetes.Interf
 
 	obj, errConvert := runtime.DefaultUnstructuredConverter.ToUnstructured(argo)
 	if errConvert != nil {
-		return fmt.Errorf("failed to convert ArgoCD to unstructured: %v", errConvert)
+		return false, fmt.Errorf("failed to convert ArgoCD to unstructured: %v", errConvert)
 	}
 	newArgo := &unstructured.Unstructured{Object: obj}
 
@@ -609,15 +613,15 @@ func createOrUpdateArgoCD(client dynamic.Interface, fullClient kubernetes.Interf
 
 	if !haveArgo(client, name, namespace) {
 		_, err := client.Resource(gvr).Namespace(namespace).Create(context.TODO(), newArgo, metav1.CreateOptions{})
-		return err
+		return err == nil, err
 	}
 
 	oldArgo, oldUnstructured, errGet := getArgoCDFunc(client, name, namespace)
 	if errGet != nil {
-		return fmt.Errorf("failed to get existing ArgoCD %s/%s: %v", namespace, name, errGet)
+		return false, fmt.Errorf("failed to get existing ArgoCD %s/%s: %v", namespace, name, errGet)
 	}
 	if oldUnstructured == nil {
-		return fmt.Errorf("getArgoCD returned nil ArgoCD object for %s/%s", namespace, name)
+		return false, fmt.Errorf("getArgoCD returned nil ArgoCD object for %s/%s", namespace, name)
 	}
 
 	// Preserve spec fields not known to this vendored argocd-operator version
@@ -632,12 +636,12 @@ func createOrUpdateArgoCD(client dynamic.Interface, fullClient kubernetes.Interf
 	}
 
 	if compareArgoCD(argo, oldArgo) {
-		return nil
+		return false, nil
 	}
 
 	newArgo.SetResourceVersion(oldUnstructured.GetResourceVersion())
 	_, err := client.Resource(gvr).Namespace(namespace).Update(context.TODO(), newArgo, metav1.UpdateOptions{})
-	return err
+	return err == nil, err
 }
 
 // argocdIconBase64 is the ArgoCD logo used in the OpenShift console application menu
@@ -717,7 +721,7 @@ func getArgoCD(client dynamic.Interface, name, namespace string) (*argooperator.
 	return argo, unstructuredArgo, nil
 }
 
-func newApplicationParameters(p *api.Pattern) []argoapi.HelmParameter {
+func newApplicationParameters(p *api.Pattern, parameterValues map[string]string) []argoapi.HelmParameter {
 	parameters := []argoapi.HelmParameter{
 		{
 			Name:  "global.pattern",
@@ -737,7 +741,7 @@ func newApplicationParameters(p *api.Pattern) []argoapi.HelmParameter {
 		},
 		{
 			Name:  "global.targetRevision",
-			Value: p.Spec.GitConfig.TargetRevision,
+			Value: getDeployedRevision(p),
 		},
 		{
 			Name:  "global.hubClusterDomain",
@@ -798,7 +802,7 @@ func newApplicationParameters(p *api.Pattern) []argoapi.HelmParameter {
 			Value: boolTrue,
 		})
 	}
-	for _, extra := range p.Spec.ExtraParameters {
+	for _, extra := range getExtraParameters(p, parameterValues) {
 		if !updateHelmParameter(extra, parameters) {
 			log.Printf("Parameter %q = %q added", extra.Name, extra.Value)
 			parameters = append(parameters, argoapi.HelmParameter{
@@ -885,15 +889,115 @@ func newApplicationValueFiles(p *api.Pattern, prefix string, useVariantsDir bool
 	return files
 }
 
-func newApplicationValues(p *api.Pattern) string {
-	s := "extraParametersNested:\n"
+// getExtraParameters returns the extra parameters of the pattern with the values resolved by
+// resolveExtraParameters in place of Value. Parameters without a resolved value are skipped
+func getExtraParameters(p *api.Pattern, parameterValues map[string]string) []api.PatternParameter {
+	parameters := make([]api.PatternParameter, 0, len(p.Spec.ExtraParameters))
 	for _, extra := range p.Spec.ExtraParameters {
-		line := fmt.Sprintf("  %s: %s\n", extra.Name, extra.Value)
+		if extra.ValueFrom != nil {
+			value, found := parameterValues[extra.Name]
+			if !found {
+				continue
+			}
+			extra.Value = value
+		}
+		parameters = append(parameters, extra)
+	}
+	return parameters
+}
+
+func newApplicationValues(p *api.Pattern, parameterValues map[string]string) string {
+	s := "extraParametersNested:\n"
+	for _, extra := range getExtraParameters(p, parameterValues) {
+		line := fmt.Sprintf("  %s: %s\n", extra.Name, yamlScalar(extra.Value))
 		s += line
 	}
 	return s
 }
 
+// yamlScalar returns value as is when YAML reads it back as a single scalar, so that numbers and booleans
+// keep their type. Anything else, like multi-line values, mappings or comments, is quoted
+func yamlScalar(value string) string {
+	var parsed any
+	if !strings.Contains(value, "\n") && yaml.Unmarshal([]byte(value), &parsed) == nil {
+		switch v := parsed.(type) {
+		case nil, bool, float64:
+			return value
+		case string:
+			if v == value {
+				return value
+			}
+		}
+	}
+	quoted, _ := json.Marshal(value)
+	return string(quoted)
+}
+
+// getPatternValues decodes the structured Helm values from the pattern spec
+func getPatternValues(p *api.Pattern) (map[string]any, error) {
+	values := map[string]any{}
+	if p.Spec.Values == nil || len(p.Spec.Values.Raw) == 0 {
+		return values, nil
+	}
+	if err := json.Unmarshal(p.Spec.Values.Raw, &values); err != nil {
+		return nil, fmt.Errorf("could not parse spec.values as a map: %w", err)
+	}
+	return values, nil
+}
+
+// getCheckoutHelmValues merges the values files of the local checkout with the structured
+// values from the pattern spec, which take precedence
+func getCheckoutHelmValues(p *api.Pattern) (map[string]any, error) {
+	gitDir := p.Status.LocalCheckoutPath
+	values, err := mergeHelmValues(newApplicationValueFiles(p, gitDir, HasVariantsFolderLayout(gitDir))...)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch value files: %w", err)
+	}
+	specValues, err := getPatternValues(p)
+	if err != nil {
+		return nil, err
+	}
+	// Contrary to intuition the dst argument (specValues) takes precedence
+	return chartutil.CoalesceTables(specValues, values), nil
+}
+
+// newApplicationValuesObject merges the structured Helm values from the pattern spec with the
+// extraParametersNested block passed down to the single applications. Returns nil when the
+// pattern has no structured values, in which case newApplicationValues is used as is
+func newApplicationValuesObject(p *api.Pattern, parameterValues map[string]string) (*runtime.RawExtension, error) {
+	if p.Spec.Values == nil || len(p.Spec.Values.Raw) == 0 {
+		return nil, nil
+	}
+	values, err := getPatternValues(p)
+	if err != nil {
+		return nil, err
+	}
+	extraParametersNested := map[string]any{}
+	for _, extra := range getExtraParameters(p, parameterValues) {
+		extraParametersNested[extra.Name] = extra.Value
+	}
+	values["extraParametersNested"] = extraParametersNested
+	raw, err := json.Marshal(values)
+	if err != nil {
+		return nil, err
+	}
+	return &runtime.RawExtension{Raw: raw}, nil
+}
+
+// newApplicationValueMap returns the values used to template the sharedValueFiles. The Helm
+// parameters take precedence over the structured values from the pattern spec, as they do in Argo
+func newApplicationValueMap(p *api.Pattern, parameterValues map[string]string) map[string]any {
+	valueMap := convertArgoHelmParametersToMap(newApplicationParameters(p, parameterValues))
+	values, err := getPatternValues(p)
+	if err != nil {
+		// Unreachable from the reconcile loop, preValidation fails the pattern on such values
+		log.Printf("Ignoring spec.values: %s", err)
+		return valueMap
+	}
+	// Contrary to intuition the dst argument (valueMap) takes precedence
+	return chartutil.CoalesceTables(valueMap, values)
+}
+
 // Fetches the clusterGroup.sharedValueFiles values from a checked out git repo
 //  1. We get all the valueFiles from the pattern
 //  2. We parse them and merge them in order
@@ -901,7 +1005,7 @@ func newApplicationValues(p *api.Pattern) string {
 //     libraries. E.g. a string '/overrides/values-{{ $.Values.global.clusterPlatform }}.yaml'
 //     will be converted to '/overrides/values-AWS.yaml'
 //  4. We return the list of templated strings back as an array
-func getSharedValueFiles(p *api.Pattern, prefix string) ([]string, error) {
+func getSharedValueFiles(p *api.Pattern, prefix string, parameterValues map[string]string) ([]string, error) {
 	gitDir := p.Status.LocalCheckoutPath
 	if _, err := os.Stat(gitDir); err != nil {
 		return nil, fmt.Errorf("%s path does not exist", gitDir)
@@ -932,7 +1036,7 @@ func getSharedValueFiles(p *api.Pattern, prefix string) ([]string, error) {
 		if !ok {
 			return nil, fmt.Errorf("type assertion failed at index %d: Not a string", i)
 		}
-		valueMap := convertArgoHelmParametersToMap(newApplicationParameters(p))
+		valueMap := newApplicationValueMap(p, parameterValues)
 		templatedString, err := helmTpl(str, valueFiles, valueMap)
 
 		// we only log an error, but try to keep going
@@ -1010,24 +1114,24 @@ func commonApplicationSpec(p *api.Pattern, sources []argoapi.ApplicationSource)
 	return spec
 }
 
-func commonApplicationSourceHelm(p *api.Pattern, prefix string) *argoapi.ApplicationSourceHelm {
+func commonApplicationSourceHelm(p *api.Pattern, prefix string, parameterValues map[string]string) *argoapi.ApplicationSourceHelm {
 	useVariantsDir := HasVariantsFolderLayout(p.Status.LocalCheckoutPath)
 	valueFiles := newApplicationValueFiles(p, prefix, useVariantsDir)
-	sharedValueFiles, err := getSharedValueFiles(p, prefix)
+	sharedValueFiles, err := getSharedValueFiles(p, prefix, parameterValues)
 	if err != nil {
 		log.Printf("Could not fetch sharedValueFiles: %s", err)
 	} else {
 		valueFiles = append(valueFiles, sharedValueFiles...)
 	}
 
-	return &argoapi.ApplicationSourceHelm{
+	helm := &argoapi.ApplicationSourceHelm{
 		ValueFiles: valueFiles,
 
 		// Parameters is a list of Helm parameters which are passed to the helm template command upon manifest generation
-		Parameters: newApplicationParameters(p),
+		Parameters: newApplicationParameters(p, parameterValues),
 
 		// This is to be able to pass down the extraParams to the single applications
-		Values: newApplicationValues(p),
+		Values: newApplicationValues(p, parameterValues),
 		// ReleaseName is the Helm release name to use. If omitted it will use the application name
 		// ReleaseName string `json:"releaseName,omitempty" protobuf:"bytes,3,opt,name=releaseName"`
 		// Values specifies Helm values to be passed to helm template, typically defined as a block
@@ -1044,11 +1148,22 @@ func commonApplicationSourceHelm(p *api.Pattern, prefix string) *argoapi.Applica
 		// SkipCrds skips custom resource definition installation step (Helm's --skip-crds)
 		// SkipCrds bool `json:"skipCrds,omitempty" protobuf:"bytes,9,opt,name=skipCrds"`
 	}
+
+	// Argo ignores Values when ValuesObject is set, so the structured values carry the extraParams too
+	valuesObject, err := newApplicationValuesObject(p, parameterValues)
+	if err != nil {
+		// Only reached while finalizing, preValidation fails the reconcile on such values
+		log.Printf("Ignoring spec.values: %s", err)
+	} else if valuesObject != nil {
+		helm.Values = ""
+		helm.ValuesObject = valuesObject
+	}
+	return helm
 }
 
 func newArgoOperatorApplication(p *api.Pattern, spec *argoapi.ApplicationSpec) *argoapi.Application {
 	labels := make(map[string]string)
-	labels["validatedpatterns.io/pattern"] = p.Name
+	labels[PatternApplicationLabel] = p.Name
 	app := argoapi.Application{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      applicationName(p),
@@ -1061,7 +1176,25 @@ func newArgoOperatorApplication(p *api.Pattern, spec *argoapi.ApplicationSpec) *
 	return &app
 }
 
-func newSourceApplication(p *api.Pattern) *argoapi.Application {
+// getTargetRevision returns the revision of the pattern repository that is checked out: the rollback
+// revision while one is pinned, the target revision otherwise
+func getTargetRevision(p *api.Pattern) string {
+	if p.Spec.GitConfig.RollbackRevision != "" {
+		return p.Spec.GitConfig.RollbackRevision
+	}
+	return p.Spec.GitConfig.TargetRevision
+}
+
+// getDeployedRevision returns the revision Argo deploys the pattern repository at: the last commit recorded in the
+// status as verified, following a branch would deploy later unverified commits. The target revision otherwise
+func getDeployedRevision(p *api.Pattern) string {
+	if p.Status.Revision != nil && p.Status.Revision.Verified {
+		return p.Status.Revision.Revision
+	}
+	return getTargetRevision(p)
+}
+
+func newSourceApplication(p *api.Pattern, parameterValues map[string]string) *argoapi.Application {
 	// Argo uses...
 	// r := regexp.MustCompile("(/|:)")
 	// root := filepath.Join(os.TempDir(), r.ReplaceAllString(NormalizeGitURL(rawRepoURL), "_"))
@@ -1069,8 +1202,8 @@ func newSourceApplication(p *api.Pattern) *argoapi.Application {
 	source := argoapi.ApplicationSource{
 		RepoURL:        p.Spec.GitConfig.TargetRepo,
 		Path:           "common/clustergroup",
-		TargetRevision: p.Spec.GitConfig.TargetRevision,
-		Helm:           commonApplicationSourceHelm(p, ""),
+		TargetRevision: getDeployedRevision(p),
+		Helm:           commonApplicationSourceHelm(p, "", parameterValues),
 	}
 	spec := commonApplicationSpec(p, []argoapi.ApplicationSource{source})
 
@@ -1078,13 +1211,13 @@ func newSourceApplication(p *api.Pattern) *argoapi.Application {
 	return newArgoOperatorApplication(p, spec)
 }
 
-func newMultiSourceApplication(p *api.Pattern) *argoapi.Application {
+func newMultiSourceApplication(p *api.Pattern, parameterValues map[string]string) *argoapi.Application {
 	sources := []argoapi.ApplicationSource{}
 	var baseSource *argoapi.ApplicationSource
 
 	valuesSource := &argoapi.ApplicationSource{
 		RepoURL:        p.Spec.GitConfig.TargetRepo,
-		TargetRevision: p.Spec.GitConfig.TargetRevision,
+		TargetRevision: getDeployedRevision(p),
 		Ref:            PatternRef,
 	}
 	sources = append(sources, *valuesSource)
@@ -1098,14 +1231,14 @@ func newMultiSourceApplication(p *api.Pattern) *argoapi.Application {
 			RepoURL:        p.Spec.MultiSourceConfig.HelmRepoUrl,
 			Chart:          "clustergroup",
 			TargetRevision: getClusterGroupChartVersion(p),
-			Helm:           commonApplicationSourceHelm(p, "$patternref"),
+			Helm:           commonApplicationSourceHelm(p, "$patternref", parameterValues),
 		}
 	} else {
 		baseSource = &argoapi.ApplicationSource{
 			RepoURL:        p.Spec.MultiSourceConfig.ClusterGroupGitRepoUrl,
 			Path:           ".",
 			TargetRevision: p.Spec.MultiSourceConfig.ClusterGroupChartGitRevision,
-			Helm:           commonApplicationSourceHelm(p, "$patternref"),
+			Helm:           commonApplicationSourceHelm(p, "$patternref", parameterValues),
 		}
 	}
 	sources = append(sources, *baseSource)
@@ -1129,14 +1262,16 @@ func getClusterGroupChartVersion(p *api.Pattern) string {
 	return clusterGroupChartVersion
 }
 
-func newArgoApplication(p *api.Pattern) *argoapi.Application {
+// newArgoApplication returns the app of apps of the pattern. parameterValues holds the values of the extra
+// parameters read from ConfigMaps and Secrets, see resolveExtraParameters
+func newArgoApplication(p *api.Pattern, parameterValues map[string]string) *argoapi.Application {
 	// -- ArgoCD Application
 	var targetApp *argoapi.Application
 
 	if *p.Spec.MultiSourceConfig.Enabled {
-		targetApp = newMultiSourceApplication(p)
+		targetApp = newMultiSourceApplication(p, parameterValues)
 	} else {
-		targetApp = newSourceApplication(p)
+		targetApp = newSourceApplication(p, parameterValues)
 	}
 
 	return targetApp
@@ -1175,7 +1310,7 @@ func newArgoGiteaApplication(p *api.Pattern, patternsOperatorConfig PatternsOper
 		SyncPolicy: commonSyncPolicy(p),
 	}
 	labels := make(map[string]string)
-	labels["validatedpatterns.io/pattern"] = p.Name
+	labels[PatternApplicationLabel] = p.Name
 	app := argoapi.Application{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      GiteaApplicationName,
@@ -1220,6 +1355,24 @@ func getApplication(client argoclient.Interface, name, namespace string) (*argoa
 	}
 }
 
+// getSyncedRevision returns the commit Argo last synced for repoURL in the given application,
+// or "" if Argo has not synced it yet. For multi-source applications the revision of the
+// source pointing at repoURL is returned.
+func getSyncedRevision(app *argoapi.Application, repoURL string) string {
+	if app == nil {
+		return ""
+	}
+	if !app.Spec.HasMultipleSources() {
+		return app.Status.Sync.Revision
+	}
+	for i := range app.Spec.Sources {
+		if app.Spec.Sources[i].RepoURL == repoURL && i < len(app.Status.Sync.Revisions) {
+			return app.Status.Sync.Revisions[i]
+		}
+	}
+	return ""
+}
+
 func createApplication(client argoclient.Interface, app *argoapi.Application, namespace string) error {
 	saved, err := client.ArgoprojV1alpha1().Applications(namespace).Create(context.Background(), app, metav1.CreateOptions{})
 	if err != nil {
@@ -1407,6 +1560,31 @@ func compareHelmSource(goal, actual *argoapi.ApplicationSourceHelm) bool {
 	if !compareHelmParameters(goal.Parameters, actual.Parameters) {
 		return false
 	}
+	if !compareHelmValuesObject(goal.ValuesObject, actual.ValuesObject) {
+		return false
+	}
+	return true
+}
+
+func compareHelmValuesObject(goal, actual *runtime.RawExtension) bool {
+	if goal == nil && actual == nil {
+		return true
+	}
+	if (goal == nil) != (actual == nil) {
+		log.Printf("Helm values object changed\n")
+		return false
+	}
+	var goalValues, actualValues map[string]any
+	if err := json.Unmarshal(goal.Raw, &goalValues); err != nil {
+		return false
+	}
+	if err := json.Unmarshal(actual.Raw, &actualValues); err != nil {
+		return false
+	}
+	if !apiequality.Semantic.DeepEqual(goalValues, actualValues) {
+		log.Printf("Helm values object changed\n")
+		return false
+	}
 	return true
 }
 
@@ -3,6 +3,7 @@ package controllers
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"os"
@@ -105,8 +106,8 @@ var _ = Describe("Argo Pattern", func() {
 			TargetRevision: pattern.Spec.GitConfig.TargetRevision,
 			Helm: &argoapi.ApplicationSourceHelm{
 				ValueFiles:              newApplicationValueFiles(pattern, "", false),
-				Parameters:              newApplicationParameters(pattern),
-				Values:                  newApplicationValues(pattern),
+				Parameters:              newApplicationParameters(pattern, nil),
+				Values:                  newApplicationValues(pattern, nil),
 				IgnoreMissingValueFiles: true,
 			},
 		}
@@ -153,7 +154,7 @@ var _ = Describe("Argo Pattern", func() {
 				// This is needed to debug any failures as gomega truncates the diff output
 				format.MaxDepth = 100
 				format.MaxLength = 0
-				Expect(newArgoApplication(pattern)).To(Equal(argoApp))
+				Expect(newArgoApplication(pattern, nil)).To(Equal(argoApp))
 			})
 		})
 		Context("Default multi source", func() {
@@ -176,7 +177,7 @@ var _ = Describe("Argo Pattern", func() {
 					*appSource,
 				}
 				multiSourceArgoApp.Spec.Sources[1].Helm.ValueFiles = newApplicationValueFiles(pattern, "$patternref", false)
-				Expect(newMultiSourceApplication(pattern)).To(Equal(multiSourceArgoApp))
+				Expect(newMultiSourceApplication(pattern, nil)).To(Equal(multiSourceArgoApp))
 			})
 		})
 		Context("multiSource with MultiSourceClusterGroupChartGitRevision set", func() {
@@ -200,7 +201,40 @@ var _ = Describe("Argo Pattern", func() {
 					*appSource,
 				}
 				multiSourceArgoApp.Spec.Sources[1].Helm.ValueFiles = newApplicationValueFiles(pattern, "$patternref", false)
-				Expect(newMultiSourceApplication(pattern)).To(Equal(multiSourceArgoApp))
+				Expect(newMultiSourceApplication(pattern, nil)).To(Equal(multiSourceArgoApp))
+			})
+		})
+		Context("with a rollback revision pinned", func() {
+			const rollback = "d0f3fb283cfb17189cba89aa5ff57fd8dcb2a7fd"
+			BeforeEach(func() {
+				pattern.Spec.GitConfig.RollbackRevision = rollback
+			})
+			It("deploys the pinned commit from a single source", func() {
+				app := newSourceApplication(pattern, nil)
+				Expect(app.Spec.Source.TargetRevision).To(Equal(rollback))
+				Expect(app.Spec.Source.Helm.Parameters).To(ContainElement(argoapi.HelmParameter{Name: "global.targetRevision", Value: rollback}))
+			})
+			It("deploys the pinned commit from the pattern ref of a multi source", func() {
+				app := newMultiSourceApplication(pattern, nil)
+				Expect(app.Spec.Sources[0].Ref).To(Equal(PatternRef))
+				Expect(app.Spec.Sources[0].TargetRevision).To(Equal(rollback))
+				Expect(app.Spec.Sources[1].Helm.Parameters).To(ContainElement(argoapi.HelmParameter{Name: "global.targetRevision", Value: rollback}))
+			})
+			It("follows the target revision again once the pin is cleared", func() {
+				pattern.Spec.GitConfig.RollbackRevision = ""
+				Expect(newSourceApplication(pattern, nil).Spec.Source.TargetRevision).To(Equal(pattern.Spec.GitConfig.TargetRevision))
+			})
+		})
+		Context("with a verified revision", func() {
+			const verified = "0123456789abcdef0123456789abcdef01234567"
+			BeforeEach(func() {
+				pattern.Status.Revision = &api.PatternRevision{Revision: verified, Verified: true}
+			})
+			It("deploys the verified commit instead of following the target revision", func() {
+				app := newSourceApplication(pattern, nil)
+				Expect(app.Spec.Source.TargetRevision).To(Equal(verified))
+				Expect(app.Spec.Source.Helm.Parameters).To(ContainElement(argoapi.HelmParameter{Name: "global.targetRevision", Value: verified}))
+				Expect(newMultiSourceApplication(pattern, nil).Spec.Sources[0].TargetRevision).To(Equal(verified))
 			})
 		})
 	})
@@ -473,7 +507,7 @@ var _ = Describe("Argo Pattern", func() {
 				}
 			})
 			It("Test default newApplicationParameters", func() {
-				Expect(newApplicationParameters(pattern)).To(Equal(append(appParameters,
+				Expect(newApplicationParameters(pattern, nil)).To(Equal(append(appParameters,
 					argoapi.HelmParameter{
 						Name:        "global.multiSourceSupport",
 						Value:       "false",
@@ -515,7 +549,7 @@ var _ = Describe("Argo Pattern", func() {
 						Value: "test2value",
 					},
 				}
-				Expect(newApplicationParameters(pattern)).To(Equal(append(appParameters,
+				Expect(newApplicationParameters(pattern, nil)).To(Equal(append(appParameters,
 					argoapi.HelmParameter{
 						Name:        "global.multiSourceSupport",
 						Value:       "false",
@@ -556,7 +590,7 @@ var _ = Describe("Argo Pattern", func() {
 			It("Test newApplicationParameters with multiSource", func() {
 				tmpBool := true
 				pattern.Spec.MultiSourceConfig.Enabled = &tmpBool
-				Expect(newApplicationParameters(pattern)).To(Equal(append(appParameters,
+				Expect(newApplicationParameters(pattern, nil)).To(Equal(append(appParameters,
 					argoapi.HelmParameter{
 						Name:        "global.multiSourceSupport",
 						Value:       "true",
@@ -592,7 +626,7 @@ var _ = Describe("Argo Pattern", func() {
 				defer os.RemoveAll(td)
 				Expect(os.MkdirAll(filepath.Join(td, "variants"), 0755)).To(Succeed())
 				pattern.Status.LocalCheckoutPath = td
-				params := newApplicationParameters(pattern)
+				params := newApplicationParameters(pattern, nil)
 				found := false
 				for _, p := range params {
 					if p.Name == ParamVpNewFolderDir {
@@ -609,7 +643,7 @@ var _ = Describe("Argo Pattern", func() {
 				Expect(err).ToNot(HaveOccurred())
 				defer os.RemoveAll(td)
 				pattern.Status.LocalCheckoutPath = td
-				params := newApplicationParameters(pattern)
+				params := newApplicationParameters(pattern, nil)
 				for _, p := range params {
 					Expect(p.Name).ToNot(Equal(ParamVpNewFolderDir))
 				}
@@ -621,7 +655,7 @@ var _ = Describe("Argo Pattern", func() {
 			var sources []argoapi.ApplicationSource
 
 			BeforeEach(func() {
-				multiSourceArgoApp = newMultiSourceApplication(pattern)
+				multiSourceArgoApp = newMultiSourceApplication(pattern, nil)
 				sources = multiSourceArgoApp.Spec.Sources
 			})
 			It("compareSource() function identical", func() {
@@ -655,7 +689,7 @@ var _ = Describe("Argo Pattern", func() {
 			var syncPolicy *argoapi.SyncPolicy
 
 			BeforeEach(func() {
-				multiSourceArgoApp = newMultiSourceApplication(pattern)
+				multiSourceArgoApp = newMultiSourceApplication(pattern, nil)
 				syncPolicy = multiSourceArgoApp.Spec.SyncPolicy
 			})
 			It("compareSyncPolicy() function identical", func() {
@@ -679,7 +713,7 @@ var _ = Describe("Argo Pattern", func() {
 			var automatedSyncPolicy *argoapi.SyncPolicyAutomated
 
 			BeforeEach(func() {
-				multiSourceArgoApp = newMultiSourceApplication(pattern)
+				multiSourceArgoApp = newMultiSourceApplication(pattern, nil)
 				automatedSyncPolicy = multiSourceArgoApp.Spec.SyncPolicy.Automated
 			})
 			It("compareAutomatedSyncPolicy() function identical", func() {
@@ -732,7 +766,7 @@ var _ = Describe("Argo Pattern", func() {
 			var syncOptions argoapi.SyncOptions
 
 			BeforeEach(func() {
-				multiSourceArgoApp = newMultiSourceApplication(pattern)
+				multiSourceArgoApp = newMultiSourceApplication(pattern, nil)
 				syncOptions = multiSourceArgoApp.Spec.SyncPolicy.SyncOptions
 			})
 			It("compareSyncOptions() function identical", func() {
@@ -833,7 +867,7 @@ var _ = Describe("NewApplicationValues", func() {
 				},
 			}
 
-			result := newApplicationValues(pattern)
+			result := newApplicationValues(pattern, nil)
 			Expect(result).To(Equal("extraParametersNested:\n"))
 		})
 	})
@@ -848,7 +882,7 @@ var _ = Describe("NewApplicationValues", func() {
 				},
 			}
 
-			result := newApplicationValues(pattern)
+			result := newApplicationValues(pattern, nil)
 			expected := "extraParametersNested:\n  param1: value1\n"
 			Expect(result).To(Equal(expected))
 		})
@@ -865,7 +899,7 @@ var _ = Describe("NewApplicationValues", func() {
 				},
 			}
 
-			result := newApplicationValues(pattern)
+			result := newApplicationValues(pattern, nil)
 			expected := "extraParametersNested:\n  param1: value1\n  param2: value2\n"
 			Expect(result).To(Equal(expected))
 		})
@@ -882,13 +916,129 @@ var _ = Describe("NewApplicationValues", func() {
 				},
 			}
 
-			result := newApplicationValues(pattern)
+			result := newApplicationValues(pattern, nil)
 			expected := "extraParametersNested:\n  param-1: value-1\n  param_2: value_2\n"
 			Expect(result).To(Equal(expected))
 		})
 	})
 })
 
+var _ = Describe("Extra parameters read from a ConfigMap", func() {
+	var pattern *api.Pattern
+	parameterValues := map[string]string{"global.domain": "example.com"}
+
+	BeforeEach(func() {
+		tmpFalse := false
+		pattern = &api.Pattern{
+			Spec: api.PatternSpec{
+				MultiSourceConfig: api.MultiSourceConfig{Enabled: &tmpFalse},
+				ExtraParameters: []api.PatternParameter{
+					{Name: "global.domain", ValueFrom: &api.PatternParameterSource{
+						ConfigMapKeyRef: &v1.ConfigMapKeySelector{LocalObjectReference: v1.LocalObjectReference{Name: "settings"}, Key: "domain"},
+					}},
+				},
+			},
+		}
+	})
+
+	It("should pass the resolved value as Helm parameter", func() {
+		Expect(newApplicationParameters(pattern, parameterValues)).To(ContainElement(argoapi.HelmParameter{Name: "global.domain", Value: "example.com"}))
+		Expect(newApplicationValues(pattern, parameterValues)).To(Equal("extraParametersNested:\n  global.domain: example.com\n"))
+		Expect(pattern.Spec.ExtraParameters[0].Value).To(BeEmpty())
+	})
+
+	It("should skip the parameters without a resolved value", func() {
+		Expect(getExtraParameters(pattern, nil)).To(BeEmpty())
+		Expect(newApplicationValues(pattern, nil)).To(Equal("extraParametersNested:\n"))
+	})
+
+	It("should quote the values that are not a single YAML scalar", func() {
+		values := map[string]string{"global.domain": "line one\nkey: value # comment"}
+		Expect(newApplicationValues(pattern, values)).To(Equal("extraParametersNested:\n  global.domain: \"line one\\nkey: value # comment\"\n"))
+		Expect(yamlScalar("key: value")).To(Equal(`"key: value"`))
+		Expect(yamlScalar("true")).To(Equal("true"))
+		Expect(yamlScalar("42")).To(Equal("42"))
+		Expect(yamlScalar("")).To(Equal(""))
+	})
+})
+
+var _ = Describe("Structured pattern values", func() {
+	var pattern *api.Pattern
+
+	BeforeEach(func() {
+		tmpFalse := false
+		pattern = &api.Pattern{
+			Spec: api.PatternSpec{
+				MultiSourceConfig: api.MultiSourceConfig{Enabled: &tmpFalse},
+				ExtraParameters: []api.PatternParameter{
+					{Name: "global.foo", Value: "param"},
+				},
+			},
+		}
+	})
+
+	Context("when spec.values is not set", func() {
+		It("should not return a values object", func() {
+			valuesObject, err := newApplicationValuesObject(pattern, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(valuesObject).To(BeNil())
+		})
+	})
+
+	Context("when spec.values is set", func() {
+		BeforeEach(func() {
+			pattern.Spec.Values = &runtime.RawExtension{Raw: []byte(`{"global":{"foo":"values","list":["a","b"]},"note":"a: b\nc"}`)}
+		})
+
+		It("should keep lists, maps and multi-line strings intact and carry the extra parameters", func() {
+			valuesObject, err := newApplicationValuesObject(pattern, nil)
+			Expect(err).ToNot(HaveOccurred())
+			var values map[string]any
+			Expect(json.Unmarshal(valuesObject.Raw, &values)).To(Succeed())
+			Expect(values["global"]).To(Equal(map[string]any{"foo": "values", "list": []any{"a", "b"}}))
+			Expect(values["note"]).To(Equal("a: b\nc"))
+			Expect(values["extraParametersNested"]).To(Equal(map[string]any{"global.foo": "param"}))
+		})
+
+		It("should let the Helm parameters win in the map used to template sharedValueFiles", func() {
+			valueMap := newApplicationValueMap(pattern, nil)
+			global := valueMap["global"].(map[string]any)
+			Expect(global["foo"]).To(Equal("param"))
+			Expect(global["list"]).To(Equal([]any{"a", "b"}))
+			Expect(valueMap["note"]).To(Equal("a: b\nc"))
+		})
+
+		It("should use the values object instead of the values string in the Argo Helm source", func() {
+			helm := commonApplicationSourceHelm(pattern, "", nil)
+			Expect(helm.Values).To(BeEmpty())
+			Expect(helm.ValuesObject).ToNot(BeNil())
+		})
+	})
+
+	Context("when spec.values is not a map", func() {
+		It("should return an error", func() {
+			pattern.Spec.Values = &runtime.RawExtension{Raw: []byte(`["a"]`)}
+			_, err := newApplicationValuesObject(pattern, nil)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("when comparing values objects", func() {
+		It("should ignore key ordering", func() {
+			Expect(compareHelmValuesObject(&runtime.RawExtension{Raw: []byte(`{"a":1,"b":2}`)},
+				&runtime.RawExtension{Raw: []byte(`{"b":2,"a":1}`)})).To(BeTrue())
+		})
+		It("should detect changed values", func() {
+			Expect(compareHelmValuesObject(&runtime.RawExtension{Raw: []byte(`{"a":1}`)},
+				&runtime.RawExtension{Raw: []byte(`{"a":2}`)})).To(BeFalse())
+		})
+		It("should detect a values object being added or removed", func() {
+			Expect(compareHelmValuesObject(nil, &runtime.RawExtension{Raw: []byte(`{"a":1}`)})).To(BeFalse())
+			Expect(compareHelmValuesObject(nil, nil)).To(BeTrue())
+		})
+	})
+})
+
 var _ = Describe("NewArgoCD", func() {
 	var (
 		name                   string
@@ -1050,7 +1200,7 @@ var _ = Describe("CreateOrUpdateArgoCD", func() {
 
 	Context("when the ArgoCD instance does not exist", func() {
 		It("should create a new ArgoCD instance", func() {
-			err := createOrUpdateArgoCD(dynamicClient, nil, name, namespace, patternsOperatorConfig)
+			_, err := createOrUpdateArgoCD(dynamicClient, nil, name, namespace, patternsOperatorConfig)
 			Expect(err).ToNot(HaveOccurred())
 
 			argoCD, err := dynamicClient.Resource(gvr).Namespace(namespace).Get(context.TODO(), name, metav1.GetOptions{})
@@ -1078,7 +1228,7 @@ var _ = Describe("CreateOrUpdateArgoCD", func() {
 		})
 
 		It("should update the existing ArgoCD instance", func() {
-			err := createOrUpdateArgoCD(dynamicClient, nil, name, namespace, patternsOperatorConfig)
+			_, err := createOrUpdateArgoCD(dynamicClient, nil, name, namespace, patternsOperatorConfig)
 			Expect(err).ToNot(HaveOccurred())
 
 			argoCD, err := dynamicClient.Resource(gvr).Namespace(namespace).Get(context.TODO(), name, metav1.GetOptions{})
@@ -1113,7 +1263,7 @@ var _ = Describe("CreateOrUpdateArgoCD", func() {
 		})
 
 		It("should preserve spec fields not managed by patterns-operator during update", func() {
-			err := createOrUpdateArgoCD(dynamicClient, nil, name, namespace, patternsOperatorConfig)
+			_, err := createOrUpdateArgoCD(dynamicClient, nil, name, namespace, patternsOperatorConfig)
 			Expect(err).ToNot(HaveOccurred())
 
 			argoCD, err := dynamicClient.Resource(gvr).Namespace(namespace).Get(context.TODO(), name, metav1.GetOptions{})
@@ -1156,7 +1306,7 @@ var _ = Describe("CreateOrUpdateArgoCD", func() {
 		})
 
 		It("should propagate the error and not update the existing argocd", func() {
-			err := createOrUpdateArgoCD(dynamicClient, nil, name, namespace, patternsOperatorConfig)
+			_, err := createOrUpdateArgoCD(dynamicClient, nil, name, namespace, patternsOperatorConfig)
 			Expect(err).To(HaveOccurred())
 
 			argoCD, err := dynamicClient.Resource(gvr).Namespace(namespace).Get(context.TODO(), name, metav1.GetOptions{})
@@ -1263,14 +1413,14 @@ var _ = Describe("CreateOrUpdateArgoCD skips update when unchanged", func() {
 	It("should skip the update when the existing ArgoCD matches desired state", func() {
 		config := DefaultPatternsOperatorConfig
 
-		err := createOrUpdateArgoCD(dynamicClient, nil, name, namespace, config)
+		_, err := createOrUpdateArgoCD(dynamicClient, nil, name, namespace, config)
 		Expect(err).ToNot(HaveOccurred())
 
 		argoCD, err := dynamicClient.Resource(gvr).Namespace(namespace).Get(context.TODO(), name, metav1.GetOptions{})
 		Expect(err).ToNot(HaveOccurred())
 		rvAfterCreate := argoCD.GetResourceVersion()
 
-		err = createOrUpdateArgoCD(dynamicClient, nil, name, namespace, config)
+		_, err = createOrUpdateArgoCD(dynamicClient, nil, name, namespace, config)
 		Expect(err).ToNot(HaveOccurred())
 
 		argoCD, err = dynamicClient.Resource(gvr).Namespace(namespace).Get(context.TODO(), name, metav1.GetOptions{})
@@ -1319,25 +1469,25 @@ var _ = Describe("CompareApplication", func() {
 
 	Context("when one application is nil and the other is not", func() {
 		It("should return false when goal is nil", func() {
-			app := newArgoApplication(pattern)
+			app := newArgoApplication(pattern, nil)
 			Expect(compareApplication(nil, app)).To(BeFalse())
 		})
 		It("should return false when actual is nil", func() {
-			app := newArgoApplication(pattern)
+			app := newArgoApplication(pattern, nil)
 			Expect(compareApplication(app, nil)).To(BeFalse())
 		})
 	})
 
 	Context("when both applications are identical", func() {
 		It("should return true", func() {
-			app := newArgoApplication(pattern)
+			app := newArgoApplication(pattern, nil)
 			Expect(compareApplication(app, app)).To(BeTrue())
 		})
 	})
 
 	Context("when applications have different sources", func() {
 		It("should return false", func() {
-			app1 := newArgoApplication(pattern)
+			app1 := newArgoApplication(pattern, nil)
 			app2 := app1.DeepCopy()
 			app2.Spec.Source.RepoURL = "https://different.repo/url"
 			Expect(compareApplication(app1, app2)).To(BeFalse())
@@ -1346,7 +1496,7 @@ var _ = Describe("CompareApplication", func() {
 
 	Context("when applications have different sync policies", func() {
 		It("should return false", func() {
-			app1 := newArgoApplication(pattern)
+			app1 := newArgoApplication(pattern, nil)
 			app2 := app1.DeepCopy()
 			app2.Spec.SyncPolicy = nil
 			Expect(compareApplication(app1, app2)).To(BeFalse())
@@ -1788,7 +1938,7 @@ var _ = Describe("NewApplicationParameters with deletion", func() {
 			now := metav1.Now()
 			pattern.DeletionTimestamp = &now
 			pattern.Status.DeletionPhase = api.DeleteSpokeChildApps
-			params := newApplicationParameters(pattern)
+			params := newApplicationParameters(pattern, nil)
 			foundDelete := false
 			for _, p := range params {
 				if p.Name == "global.deletePattern" {
@@ -1806,7 +1956,7 @@ var _ = Describe("NewApplicationParameters with deletion", func() {
 			now := metav1.Now()
 			pattern.DeletionTimestamp = &now
 			pattern.Status.DeletionPhase = api.DeleteHubChildApps
-			params := newApplicationParameters(pattern)
+			params := newApplicationParameters(pattern, nil)
 			foundDelete := false
 			for _, p := range params {
 				if p.Name == "global.deletePattern" {
@@ -1994,7 +2144,7 @@ var _ = Describe("GetSharedValueFiles", func() {
 	Context("when path does not exist", func() {
 		It("should return an error", func() {
 			pattern.Status.LocalCheckoutPath = "/nonexistent/path"
-			_, err := getSharedValueFiles(pattern, "")
+			_, err := getSharedValueFiles(pattern, "", nil)
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("path does not exist"))
 		})
@@ -2006,7 +2156,7 @@ var _ = Describe("GetSharedValueFiles", func() {
 			err := os.WriteFile(filepath.Join(td, "values-global.yaml"), []byte("key: value\n"), 0600)
 			Expect(err).ToNot(HaveOccurred())
 
-			result, err := getSharedValueFiles(pattern, "")
+			result, err := getSharedValueFiles(pattern, "", nil)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(result).To(BeNil())
 		})
@@ -2021,7 +2171,7 @@ var _ = Describe("GetSharedValueFiles", func() {
 			err := os.WriteFile(filepath.Join(td, "values-global.yaml"), []byte(yamlContent), 0600)
 			Expect(err).ToNot(HaveOccurred())
 
-			result, err := getSharedValueFiles(pattern, "")
+			result, err := getSharedValueFiles(pattern, "", nil)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(result).To(HaveLen(1))
 			Expect(result[0]).To(ContainSubstring("values-shared.yaml"))
@@ -2171,7 +2321,7 @@ var _ = Describe("newApplicationValues", func() {
 				},
 			},
 		}
-		result := newApplicationValues(pattern)
+		result := newApplicationValues(pattern, nil)
 		Expect(result).To(ContainSubstring("extraParametersNested:"))
 		Expect(result).To(ContainSubstring("global.extraParam1: extraValue1"))
 		Expect(result).To(ContainSubstring("global.extraParam2: extraValue2"))
@@ -2183,7 +2333,7 @@ var _ = Describe("newApplicationValues", func() {
 				ExtraParameters: []api.PatternParameter{},
 			},
 		}
-		result := newApplicationValues(pattern)
+		result := newApplicationValues(pattern, nil)
 		Expect(result).To(Equal("extraParametersNested:\n"))
 	})
 
@@ -2195,7 +2345,7 @@ var _ = Describe("newApplicationValues", func() {
 				},
 			},
 		}
-		result := newApplicationValues(pattern)
+		result := newApplicationValues(pattern, nil)
 		Expect(result).To(Equal("extraParametersNested:\n  key: value\n"))
 	})
 })
@@ -2619,7 +2769,7 @@ var _ = Describe("createOrUpdateArgoCD with custom overlay", func() {
 			configKeyCustomArgoYaml: `extraConfig:
   customField: customValue`,
 		}
-		err := createOrUpdateArgoCD(dynamicClient, nil, name, namespace, config)
+		_, err := createOrUpdateArgoCD(dynamicClient, nil, name, namespace, config)
 		Expect(err).ToNot(HaveOccurred())
 
 		argoCD, err := dynamicClient.Resource(gvr).Namespace(namespace).Get(context.TODO(), name, metav1.GetOptions{})
@@ -2638,7 +2788,7 @@ var _ = Describe("createOrUpdateArgoCD with custom overlay", func() {
     limits:
       cpu: "2"`,
 		}
-		err := createOrUpdateArgoCD(dynamicClient, nil, name, namespace, config)
+		_, err := createOrUpdateArgoCD(dynamicClient, nil, name, namespace, config)
 		Expect(err).ToNot(HaveOccurred())
 
 		cpu, found, err := unstructured.NestedString(
@@ -2652,7 +2802,7 @@ var _ = Describe("createOrUpdateArgoCD with custom overlay", func() {
   resources:
     limits:
       cpu: "16"`
-		err = createOrUpdateArgoCD(dynamicClient, nil, name, namespace, config)
+		_, err = createOrUpdateArgoCD(dynamicClient, nil, name, namespace, config)
 		Expect(err).ToNot(HaveOccurred())
 
 		cpu, found, err = unstructured.NestedString(
@@ -2670,14 +2820,14 @@ var _ = Describe("createOrUpdateArgoCD with custom overlay", func() {
     limits:
       cpu: "4"`,
 		}
-		err := createOrUpdateArgoCD(dynamicClient, nil, name, namespace, config)
+		_, err := createOrUpdateArgoCD(dynamicClient, nil, name, namespace, config)
 		Expect(err).ToNot(HaveOccurred())
 
 		argoCD, err := dynamicClient.Resource(gvr).Namespace(namespace).Get(context.TODO(), name, metav1.GetOptions{})
 		Expect(err).ToNot(HaveOccurred())
 		rvAfterCreate := argoCD.GetResourceVersion()
 
-		err = createOrUpdateArgoCD(dynamicClient, nil, name, namespace, config)
+		_, err = createOrUpdateArgoCD(dynamicClient, nil, name, namespace, config)
 		Expect(err).ToNot(HaveOccurred())
 
 		argoCD, err = dynamicClient.Resource(gvr).Namespace(namespace).Get(context.TODO(), name, metav1.GetOptions{})
@@ -2692,7 +2842,7 @@ var _ = Describe("createOrUpdateArgoCD with custom overlay", func() {
     limits:
       cpu: "8"`,
 		}
-		err := createOrUpdateArgoCD(dynamicClient, nil, name, namespace, config)
+		_, err := createOrUpdateArgoCD(dynamicClient, nil, name, namespace, config)
 		Expect(err).ToNot(HaveOccurred())
 
 		argoCD, err := dynamicClient.Resource(gvr).Namespace(namespace).Get(context.TODO(), name, metav1.GetOptions{})
@@ -2855,3 +3005,50 @@ var _ = Describe("getChildApplications", func() {
 		})
 	})
 })
+
+var _ = Describe("getSyncedRevision", func() {
+	const repoURL = "https://github.com/validatedpatterns/multicloud-gitops"
+
+	It("should return empty for a nil application", func() {
+		Expect(getSyncedRevision(nil, repoURL)).To(BeEmpty())
+	})
+
+	It("should return the sync revision of a single source application", func() {
+		app := &argoapi.Application{
+			Spec: argoapi.ApplicationSpec{
+				Source: &argoapi.ApplicationSource{RepoURL: repoURL},
+			},
+			Status: argoapi.ApplicationStatus{
+				Sync: argoapi.SyncStatus{Revision: "abc123"},
+			},
+		}
+		Expect(getSyncedRevision(app, repoURL)).To(Equal("abc123"))
+	})
+
+	It("should return the revision of the matching source of a multi source application", func() {
+		app := &argoapi.Application{
+			Spec: argoapi.ApplicationSpec{
+				Sources: argoapi.ApplicationSources{
+					{RepoURL: repoURL, Ref: PatternRef},
+					{RepoURL: "https://charts.validatedpatterns.io/", Chart: "clustergroup"},
+				},
+			},
+			Status: argoapi.ApplicationStatus{
+				Sync: argoapi.SyncStatus{Revisions: []string{"abc123", "0.9.1"}},
+			},
+		}
+		Expect(getSyncedRevision(app, repoURL)).To(Equal("abc123"))
+	})
+
+	It("should return empty when a multi source application has not synced yet", func() {
+		app := &argoapi.Application{
+			Spec: argoapi.ApplicationSpec{
+				Sources: argoapi.ApplicationSources{
+					{RepoURL: repoURL, Ref: PatternRef},
+					{RepoURL: "https://charts.validatedpatterns.io/", Chart: "clustergroup"},
+				},
+			},
+		}
+		Expect(getSyncedRevision(app, repoURL)).To(BeEmpty())
+	})
+})
@@ -17,6 +17,7 @@ limitations under the License.
 package controllers
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	nethttp "net/http"
@@ -28,11 +29,14 @@ import (
 	"path/filepath"
 
 	stdssh "golang.org/x/crypto/ssh"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/protocol/packp/capability"
+	"github.com/go-git/go-git/v5/plumbing/transport"
 	"github.com/go-git/go-git/v5/plumbing/transport/client"
 	"github.com/go-git/go-git/v5/plumbing/transport/http"
 	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
@@ -40,6 +44,8 @@ import (
 	"github.com/bradleyfalzon/ghinstallation/v2"
 
 	argogit "github.com/argoproj/argo-cd/v3/util/git"
+
+	api "github.com/hybrid-cloud-patterns/patterns-operator/api/v1alpha1"
 )
 
 type GitAuthenticationBackend uint
@@ -49,12 +55,22 @@ const (
 	GitAuthPassword  GitAuthenticationBackend = 1
 	GitAuthSsh       GitAuthenticationBackend = 2
 	GitAuthGitHubApp GitAuthenticationBackend = 3
+	// HTTP Authorization: Bearer, used by Bitbucket Data Center access tokens
+	GitAuthBearerToken GitAuthenticationBackend = 4
+)
+
+const (
+	// Username Argo CD sends along a password without username. GitHub, GitLab and Azure DevOps accept
+	// any username with an access token
+	defaultTokenUsername = "x-access-token"
+	// Prefix of GitLab deploy tokens, which unlike access tokens need their own username
+	gitLabDeployTokenPrefix = "gldt-"
 )
 
 const gitRemoteOrigin = "origin"
 const ContextTimeout = 15 * time.Second
 const GitCustomCAFile = "/tmp/vp-git-cas.pem"
-const GitHEAD = "HEAD"
+const GitHEAD = api.DefaultTargetRevision
 const VPTmpFolder = "vp"
 
 // GitOperations interface defines the methods used from the go-git package.
@@ -84,8 +100,9 @@ func (g *GitOperationsImpl) CloneRepository(directory string, isBare bool, optio
 }
 
 // https://github.com/go-git/go-git/blob/master/_examples/commit/main.go
-func checkout(fullClient kubernetes.Interface, gitOps GitOperations, url, directory, commit string, secret map[string][]byte) error {
-	if err := cloneRepo(fullClient, gitOps, url, directory, secret); err != nil {
+func checkout(fullClient kubernetes.Interface, gitOps GitOperations, url, directory, commit string, secret map[string][]byte,
+	tlsConfig *gitTLSConfig) error {
+	if err := cloneRepo(fullClient, gitOps, url, directory, secret, tlsConfig); err != nil {
 		return err
 	}
 
@@ -94,7 +111,7 @@ func checkout(fullClient kubernetes.Interface, gitOps GitOperations, url, direct
 		return nil
 	}
 
-	if err := checkoutRevision(fullClient, gitOps, url, directory, commit, secret); err != nil {
+	if _, err := checkoutRevision(fullClient, gitOps, url, directory, commit, secret, tlsConfig); err != nil {
 		return err
 	}
 
@@ -171,33 +188,41 @@ func getCommitFromTarget(repo *git.Repository, name string) (plumbing.Hash, erro
 	return plumbing.ZeroHash, fmt.Errorf("unknown target %q", name)
 }
 
-func checkoutRevision(fullClient kubernetes.Interface, gitOps GitOperations, url, directory, commit string, secret map[string][]byte) error {
+func checkoutRevision(fullClient kubernetes.Interface, gitOps GitOperations, url, directory, commit string, secret map[string][]byte,
+	tlsConfig *gitTLSConfig) (*object.Commit, error) {
 	customClient := &nethttp.Client{
-		Transport: getHTTPSTransport(fullClient),
+		Transport: getGitHTTPSTransport(fullClient, tlsConfig),
 	}
 	// Override http(s) default protocol to use our custom client
 	client.InstallProtocol("https", http.NewClient(customClient))
+	setGitCapabilities(url)
 	repo, err := gitOps.OpenRepository(directory)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if repo == nil { // we mocked the above OpenRepository
-		return nil
+		return nil, nil
 	}
-	foptions, err := getFetchOptions(fullClient, url, secret)
+	foptions, err := getFetchOptions(fullClient, url, secret, tlsConfig)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if err = repo.Fetch(foptions); err != nil && err != git.NoErrAlreadyUpToDate {
+	fetchStart := time.Now()
+	err = repo.Fetch(foptions)
+	if err == git.NoErrAlreadyUpToDate {
+		err = nil
+	}
+	observeGitOperation("fetch", fetchStart, err)
+	if err != nil {
 		fmt.Printf("Error fetching: %v\n", err)
-		return err
+		return nil, err
 	}
 
 	w, err := repo.Worktree()
 	if err != nil {
 		fmt.Println("Error obtaining worktree")
-		return err
+		return nil, err
 	}
 
 	h, err := getCommitFromTarget(repo, commit)
@@ -207,14 +232,14 @@ func checkoutRevision(fullClient kubernetes.Interface, gitOps GitOperations, url
 	}
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	fmt.Printf("git checkout %s (%s)\n", h, commit)
 
 	if err = w.Checkout(&coptions); err != nil && err != git.NoErrAlreadyUpToDate {
 		fmt.Printf("Error during checkout")
-		return err
+		return nil, err
 	}
 	// ... retrieving the commit being pointed by HEAD, it shows that the
 	// repository is pointing to the giving commit in detached mode
@@ -222,19 +247,32 @@ func checkoutRevision(fullClient kubernetes.Interface, gitOps GitOperations, url
 	ref, err := repo.Head()
 	if err != nil {
 		fmt.Println("Error obtaining HEAD")
-		return err
+		return nil, err
 	}
 
 	fmt.Printf("%s\n", ref.Hash())
-	return err
+	return repo.CommitObject(ref.Hash())
+}
+
+// newPatternRevision describes the commit the target revision was checked out at
+func newPatternRevision(commit *object.Commit) *api.PatternRevision {
+	message, _, _ := strings.Cut(strings.TrimSpace(commit.Message), "\n")
+	return &api.PatternRevision{
+		Revision:   commit.Hash.String(),
+		Author:     fmt.Sprintf("%s <%s>", commit.Author.Name, commit.Author.Email),
+		Message:    message,
+		DeployedAt: metav1.Now(),
+	}
 }
 
-func cloneRepo(fullClient kubernetes.Interface, gitOps GitOperations, url, directory string, secret map[string][]byte) error {
+func cloneRepo(fullClient kubernetes.Interface, gitOps GitOperations, url, directory string, secret map[string][]byte,
+	tlsConfig *gitTLSConfig) error {
 	customClient := &nethttp.Client{
-		Transport: getHTTPSTransport(fullClient),
+		Transport: getGitHTTPSTransport(fullClient, tlsConfig),
 	}
 	// Override http(s) default protocol to use our custom client
 	client.InstallProtocol("https", http.NewClient(customClient))
+	setGitCapabilities(url)
 
 	gitDir := filepath.Join(directory, ".git")
 	if _, err := os.Stat(gitDir); err == nil {
@@ -243,12 +281,14 @@ func cloneRepo(fullClient kubernetes.Interface, gitOps GitOperations, url, direc
 	}
 	fmt.Printf("git clone %s into %s\n", url, directory)
 
-	options, err := getCloneOptions(fullClient, url, secret)
+	options, err := getCloneOptions(fullClient, url, secret, tlsConfig)
 	if err != nil {
 		return err
 	}
 
+	cloneStart := time.Now()
 	repo, err := gitOps.CloneRepository(directory, false, options)
+	observeGitOperation("clone", cloneStart, err)
 	if err != nil {
 		return err
 	}
@@ -267,64 +307,87 @@ func cloneRepo(fullClient kubernetes.Interface, gitOps GitOperations, url, direc
 	return nil
 }
 
-func getFetchOptions(fullClient kubernetes.Interface, url string, secret map[string][]byte) (*git.FetchOptions, error) {
+func getFetchOptions(fullClient kubernetes.Interface, url string, secret map[string][]byte, tlsConfig *gitTLSConfig) (*git.FetchOptions, error) {
 	var foptions = &git.FetchOptions{
 		RemoteName:      gitRemoteOrigin,
 		Force:           true,
-		InsecureSkipTLS: true,
+		InsecureSkipTLS: tlsConfig.insecure(),
 		Tags:            git.AllTags,
 	}
-	switch authType := detectGitAuthType(secret); authType {
-	case GitAuthPassword:
-		foptions.Auth = getHttpAuth(secret)
-	case GitAuthSsh:
-		publicKey, err := getSshPublicKey(url, secret)
-		if err != nil {
-			return nil, err
-		}
-		foptions.Auth = publicKey
-	case GitAuthGitHubApp:
-		gitHubAppAuth, err := getGitHubAppAuth(fullClient, secret)
-		if err != nil {
-			return nil, err
-		}
-
-		foptions.Auth = gitHubAppAuth
+	auth, err := getGitAuth(fullClient, url, secret)
+	if err != nil {
+		return nil, err
 	}
+	foptions.Auth = auth
 
 	return foptions, nil
 }
 
-func getCloneOptions(fullClient kubernetes.Interface, url string, secret map[string][]byte) (*git.CloneOptions, error) {
+func getCloneOptions(fullClient kubernetes.Interface, url string, secret map[string][]byte, tlsConfig *gitTLSConfig) (*git.CloneOptions, error) {
 	// Clone the given repository to the given directory
 	var options = &git.CloneOptions{
-		URL:          url,
-		RemoteName:   gitRemoteOrigin,
-		Progress:     os.Stdout,
-		Depth:        0,
-		SingleBranch: false,
-		Tags:         git.AllTags,
+		URL:             url,
+		RemoteName:      gitRemoteOrigin,
+		Progress:        os.Stdout,
+		Depth:           0,
+		SingleBranch:    false,
+		Tags:            git.AllTags,
+		InsecureSkipTLS: tlsConfig.insecure(),
 	}
+	auth, err := getGitAuth(fullClient, url, secret)
+	if err != nil {
+		return nil, err
+	}
+	options.Auth = auth
+
+	return options, nil
+}
+
+// go-git leaves out multi_ack by default, Azure DevOps refuses clients that do not support it
+// https://github.com/go-git/go-git/blob/main/_examples/azure_devops/main.go
+var defaultUnsupportedCapabilities = transport.UnsupportedCapabilities
+
+// setGitCapabilities sets the capabilities go-git negotiates for the git server of url. Like the installed
+// https client this is global, reconciles do not run concurrently
+func setGitCapabilities(url string) {
+	if isAzureDevOpsURL(url) {
+		transport.UnsupportedCapabilities = []capability.Capability{capability.ThinPack}
+		return
+	}
+	transport.UnsupportedCapabilities = defaultUnsupportedCapabilities
+}
+
+// isAzureDevOpsURL matches https://dev.azure.com/org/project/_git/repo, git@ssh.dev.azure.com:v3/org/project/repo
+// and the older https://org.visualstudio.com/project/_git/repo
+func isAzureDevOpsURL(url string) bool {
+	host, err := extractGitFQDNHostname(url)
+	if err != nil {
+		return false
+	}
+	return host == "dev.azure.com" || host == "ssh.dev.azure.com" || strings.HasSuffix(host, ".visualstudio.com")
+}
 
+// getGitAuth returns the authentication of the backend detected from the shape of the git auth secret
+func getGitAuth(fullClient kubernetes.Interface, url string, secret map[string][]byte) (transport.AuthMethod, error) {
 	switch authType := detectGitAuthType(secret); authType {
 	case GitAuthPassword:
-		options.Auth = getHttpAuth(secret)
-	case GitAuthSsh:
-		publicKey, err := getSshPublicKey(url, secret)
-		if err != nil {
-			return nil, err
+		if len(getField(secret, secretFieldUsername)) == 0 && bytes.HasPrefix(getField(secret, secretFieldPassword), []byte(gitLabDeployTokenPrefix)) {
+			return nil, fmt.Errorf("GitLab deploy tokens need the username of the token in the %s field of the token secret", secretFieldUsername)
 		}
-		options.Auth = publicKey
+		return getHttpAuth(secret), nil
+	case GitAuthBearerToken:
+		return &http.TokenAuth{Token: string(getField(secret, secretFieldBearerToken))}, nil
+	case GitAuthSsh:
+		return getSshPublicKey(url, secret)
 	case GitAuthGitHubApp:
-		gitHubAppAuth, err := getGitHubAppAuth(fullClient, secret)
-		if err != nil {
-			return nil, err
-		}
-
-		options.Auth = gitHubAppAuth
+		return getGitHubAppAuth(fullClient, secret)
 	}
-
-	return options, nil
+	// Like Argo CD, client certificates are only presented along with HTTP credentials
+	if getField(secret, secretFieldTLSClientCertData) != nil {
+		return nil, fmt.Errorf("%s needs a %s, a %s or a GitHub App in the token secret", secretFieldTLSClientCertData,
+			secretFieldPassword, secretFieldBearerToken)
+	}
+	return nil, nil
 }
 
 func getHttpAuth(secret map[string][]byte) *http.BasicAuth {
@@ -335,6 +398,9 @@ func getHttpAuth(secret map[string][]byte) *http.BasicAuth {
 		Username: string(getField(secret, secretFieldUsername)),
 		Password: string(getField(secret, secretFieldPassword)),
 	}
+	if auth.Username == "" {
+		auth.Username = defaultTokenUsername
+	}
 
 	return auth
 }
@@ -350,11 +416,30 @@ func getSshPublicKey(url string, secret map[string][]byte) (*ssh.PublicKeys, err
 	if keyError != nil {
 		return nil, fmt.Errorf("could not get publicKey: %s", keyError)
 	}
-	// FIXME(bandini): in the future we might want to support passing some known hosts
-	publicKey.HostKeyCallback = stdssh.InsecureIgnoreHostKey() //nolint:gosec
+	hostKeyCallback, err := getSshHostKeyCallback(secret)
+	if err != nil {
+		return nil, err
+	}
+	publicKey.HostKeyCallback = hostKeyCallback
 	return publicKey, nil
 }
 
+// getSshHostKeyCallback verifies the host key of the git server against the sshKnownHosts of the secret.
+// Skipping the verification has to be asked for explicitly with insecure: "true"
+func getSshHostKeyCallback(secret map[string][]byte) (stdssh.HostKeyCallback, error) {
+	if strings.EqualFold(string(getField(secret, secretFieldInsecure)), boolTrue) {
+		logOnce("SSH host key verification of the git server is disabled by the insecure field of the token secret")
+		return stdssh.InsecureIgnoreHostKey(), nil //nolint:gosec
+	}
+	knownHosts := getField(secret, secretFieldSSHKnownHosts)
+	if len(bytes.TrimSpace(knownHosts)) == 0 {
+		return nil, fmt.Errorf("no SSH known hosts to verify the git server with, add them to %s in the token secret, "+
+			"to the %s ConfigMap of Argo CD or to the ConfigMap named by %s in the operator config, or set %s: \"true\" in the token secret",
+			secretFieldSSHKnownHosts, ArgoCDKnownHostsConfigMap, configKeySSHKnownHostsConfigMap, secretFieldInsecure)
+	}
+	return newKnownHostsCallback(knownHosts)
+}
+
 func getGitHubAppAuthTransport(fullClient kubernetes.Interface, secret map[string][]byte) (*ghinstallation.Transport, error) {
 	baseURL := "https://api.github.com"
 
@@ -438,17 +523,21 @@ func repoHash(directory string) (string, error) {
 
 // Developed after https://argo-cd.readthedocs.io/en/stable/operator-manual/declarative-setup/#repositories
 // if a secret has
-// returns "" if a secret could not be parse, "ssh" if it is an ssh auth, and "password" if a username + pass auth
+// returns GitAuthNone if a secret could not be parsed, GitAuthSsh for an ssh key, GitAuthBearerToken for a bearer token,
+// GitAuthPassword for a password with or without username and GitAuthGitHubApp for a GitHub App
 func detectGitAuthType(secret map[string][]byte) GitAuthenticationBackend {
 	// SSH
 	if _, ok := secret["sshPrivateKey"]; ok {
 		return GitAuthSsh
 	}
 
-	// Username + Password
-	_, hasUser := secret[secretFieldUsername]
-	_, hasPassword := secret[secretFieldPassword]
-	if hasUser && hasPassword {
+	// Bearer token, preferred over a password like in Argo CD
+	if len(getField(secret, secretFieldBearerToken)) > 0 {
+		return GitAuthBearerToken
+	}
+
+	// Username + Password. The username may be left out with access tokens such as Azure DevOps PATs
+	if len(getField(secret, secretFieldPassword)) > 0 {
 		return GitAuthPassword
 	}
 
@@ -493,15 +582,20 @@ func getGitRemoteURL(repoPath, remoteName string) (string, error) {
 	return remote.Config().URLs[0], nil
 }
 
-func getLocalGitPath(repoURL string) string {
+// getPatternGitPath returns the folder holding the local checkouts of a pattern, so that
+// patterns sharing the cluster never touch each other's checkouts
+func getPatternGitPath(namespace, name string) string {
+	return filepath.Join(os.TempDir(), VPTmpFolder, namespace, name)
+}
+
+func getLocalGitPath(namespace, name, repoURL string) string {
 	r := regexp.MustCompile("([/:])")
 	normalizedGitURL := argogit.NormalizeGitURL(repoURL)
 	if normalizedGitURL == "" {
 		normalizedGitURL = repoURL
 	}
-	root := filepath.Join(os.TempDir(), VPTmpFolder, r.ReplaceAllString(normalizedGitURL, "_"))
-	if root == os.TempDir() {
-		return filepath.Join(os.TempDir(), VPTmpFolder, "vp-git-repo-fallback")
+	if normalizedGitURL == "" {
+		return filepath.Join(getPatternGitPath(namespace, name), "vp-git-repo-fallback")
 	}
-	return root
+	return filepath.Join(getPatternGitPath(namespace, name), r.ReplaceAllString(normalizedGitURL, "_"))
 }
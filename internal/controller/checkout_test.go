@@ -6,6 +6,9 @@ import (
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/protocol/packp/capability"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	//+kubebuilder:scaffold:imports
@@ -17,7 +20,7 @@ var gitCommitHash = "d0f3fb283cfb17189cba89aa5ff57fd8dcb2a7fd"
 var _ = Describe("Git Functions", func() {
 	Context("cloneRepo", func() {
 		It("should clone a repository and get the HEAD", func() {
-			err := cloneRepo(nil, gitOpsImpl, gitRepoURL, tempDir, nil)
+			err := cloneRepo(nil, gitOpsImpl, gitRepoURL, tempDir, nil, nil)
 			Expect(err).ToNot(HaveOccurred())
 			refHash, err := repoHash(tempDir)
 			Expect(err).ToNot(HaveOccurred())
@@ -27,7 +30,7 @@ var _ = Describe("Git Functions", func() {
 
 	Context("checkoutRevision", func() {
 		It("should checkout a specific commit", func() {
-			err := checkoutRevision(nil, gitOpsImpl, gitRepoURL, tempDir, gitCommitHash, nil) // some older existing commit hash
+			_, err := checkoutRevision(nil, gitOpsImpl, gitRepoURL, tempDir, gitCommitHash, nil, nil) // some older existing commit hash
 			Expect(err).ToNot(HaveOccurred())
 		})
 	})
@@ -49,17 +52,17 @@ var _ = Describe("Git Functions", func() {
 			cleanupTempDir(tempDir2)
 		})
 		It("should clone repository and checkout a specific commit", func() {
-			err := checkout(nil, gitOpsImpl, gitRepoURL, tempDir2, gitCommitHash, nil)
+			err := checkout(nil, gitOpsImpl, gitRepoURL, tempDir2, gitCommitHash, nil, nil)
 			Expect(err).ToNot(HaveOccurred())
 		})
 
 		It("should checkout repository without checking out if commit is empty", func() {
-			err := checkout(nil, gitOpsImpl, gitRepoURL, tempDir, "", nil)
+			err := checkout(nil, gitOpsImpl, gitRepoURL, tempDir, "", nil, nil)
 			Expect(err).ToNot(HaveOccurred())
 		})
 
 		It("should checkout a repository and switch to its remote branch", func() {
-			err := checkout(nil, gitOpsImpl, gitRepoURL, tempDir, "test-do-not-use", nil)
+			err := checkout(nil, gitOpsImpl, gitRepoURL, tempDir, "test-do-not-use", nil, nil)
 			Expect(err).ToNot(HaveOccurred())
 		})
 	})
@@ -173,22 +176,31 @@ var _ = Describe("getUserFromURL", func() {
 
 var _ = Describe("getLocalGitPath", func() {
 	It("should return a valid path for HTTPS URLs", func() {
-		result := getLocalGitPath("https://github.com/user/repo")
+		result := getLocalGitPath("default", "pattern", "https://github.com/user/repo")
 		Expect(result).ToNot(BeEmpty())
 		Expect(result).To(ContainSubstring(VPTmpFolder))
 	})
 
 	It("should return a valid path for SSH URLs", func() {
-		result := getLocalGitPath("git@github.com:user/repo.git")
+		result := getLocalGitPath("default", "pattern", "git@github.com:user/repo.git")
 		Expect(result).ToNot(BeEmpty())
 		Expect(result).To(ContainSubstring(VPTmpFolder))
 	})
 
 	It("should return different paths for different repos", func() {
-		path1 := getLocalGitPath("https://github.com/user/repo1")
-		path2 := getLocalGitPath("https://github.com/user/repo2")
+		path1 := getLocalGitPath("default", "pattern", "https://github.com/user/repo1")
+		path2 := getLocalGitPath("default", "pattern", "https://github.com/user/repo2")
 		Expect(path1).ToNot(Equal(path2))
 	})
+
+	It("should return different paths for patterns deploying the same repo", func() {
+		path1 := getLocalGitPath("default", "platform", "https://github.com/user/repo")
+		path2 := getLocalGitPath("default", "lob", "https://github.com/user/repo")
+		path3 := getLocalGitPath("other", "platform", "https://github.com/user/repo")
+		Expect(path1).ToNot(Equal(path2))
+		Expect(path1).ToNot(Equal(path3))
+		Expect(path1).To(HavePrefix(getPatternGitPath("default", "platform")))
+	})
 })
 
 var _ = Describe("detectGitAuthType", func() {
@@ -244,6 +256,25 @@ var _ = Describe("detectGitAuthType", func() {
 			Expect(detectGitAuthType(secret)).To(Equal(GitAuthNone))
 		})
 	})
+	Context("with an access token without username", func() {
+		It("should return GitAuthPassword", func() {
+			secret := map[string][]byte{
+				"password": []byte("azure-devops-pat"),
+			}
+			Expect(detectGitAuthType(secret)).To(Equal(GitAuthPassword))
+		})
+	})
+
+	Context("with a bearer token", func() {
+		It("should prefer it over a password like Argo CD", func() {
+			secret := map[string][]byte{
+				"username":    []byte("user"),
+				"password":    []byte("pass"),
+				"bearerToken": []byte("token"),
+			}
+			Expect(detectGitAuthType(secret)).To(Equal(GitAuthBearerToken))
+		})
+	})
 })
 
 var _ = Describe("getField", func() {
@@ -282,7 +313,7 @@ var _ = Describe("getHttpAuth", func() {
 var _ = Describe("getFetchOptions", func() {
 	Context("with no authentication", func() {
 		It("should return options without auth", func() {
-			opts, err := getFetchOptions(nil, "https://github.com/user/repo", nil)
+			opts, err := getFetchOptions(nil, "https://github.com/user/repo", nil, nil)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(opts).ToNot(BeNil())
 			Expect(opts.RemoteName).To(Equal("origin"))
@@ -297,7 +328,7 @@ var _ = Describe("getFetchOptions", func() {
 				"username": []byte("user"),
 				"password": []byte("pass"),
 			}
-			opts, err := getFetchOptions(nil, "https://github.com/user/repo", secret)
+			opts, err := getFetchOptions(nil, "https://github.com/user/repo", secret, nil)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(opts.Auth).ToNot(BeNil())
 		})
@@ -307,7 +338,7 @@ var _ = Describe("getFetchOptions", func() {
 var _ = Describe("getCloneOptions", func() {
 	Context("with no authentication", func() {
 		It("should return options without auth", func() {
-			opts, err := getCloneOptions(nil, "https://github.com/user/repo", nil)
+			opts, err := getCloneOptions(nil, "https://github.com/user/repo", nil, nil)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(opts).ToNot(BeNil())
 			Expect(opts.URL).To(Equal("https://github.com/user/repo"))
@@ -322,7 +353,7 @@ var _ = Describe("getCloneOptions", func() {
 				"username": []byte("user"),
 				"password": []byte("pass"),
 			}
-			opts, err := getCloneOptions(nil, "https://github.com/user/repo", secret)
+			opts, err := getCloneOptions(nil, "https://github.com/user/repo", secret, nil)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(opts.Auth).ToNot(BeNil())
 		})
@@ -333,7 +364,7 @@ var _ = Describe("getCloneOptions", func() {
 			secret := map[string][]byte{
 				"sshPrivateKey": []byte("invalid-key"),
 			}
-			_, err := getCloneOptions(nil, "git@github.com:user/repo", secret)
+			_, err := getCloneOptions(nil, "git@github.com:user/repo", secret, nil)
 			Expect(err).To(HaveOccurred())
 		})
 	})
@@ -343,7 +374,7 @@ var _ = Describe("getCloneOptions", func() {
 			secret := map[string][]byte{
 				"sshPrivateKey": nil,
 			}
-			_, err := getCloneOptions(nil, "git@github.com:user/repo", secret)
+			_, err := getCloneOptions(nil, "git@github.com:user/repo", secret, nil)
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("could not get sshPrivateKey"))
 		})
@@ -356,12 +387,66 @@ var _ = Describe("getCloneOptions", func() {
 				"githubAppInstallationID": []byte("12345"),
 				"githubAppPrivateKey":     []byte("invalid-key"),
 			}
-			_, err := getCloneOptions(nil, "https://github.com/user/repo", secret)
+			_, err := getCloneOptions(nil, "https://github.com/user/repo", secret, nil)
 			Expect(err).To(HaveOccurred())
 		})
 	})
 })
 
+var _ = Describe("getGitAuth", func() {
+	It("should send a bearer token in the Authorization header", func() {
+		auth, err := getGitAuth(nil, "https://bitbucket.example.com/scm/repo.git", map[string][]byte{"bearerToken": []byte("token")})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(auth).To(Equal(&http.TokenAuth{Token: "token"}))
+	})
+
+	It("should default the username of an access token like Argo CD", func() {
+		auth, err := getGitAuth(nil, "https://dev.azure.com/org/project/_git/repo", map[string][]byte{"password": []byte("pat")})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(auth).To(Equal(&http.BasicAuth{Username: defaultTokenUsername, Password: "pat"}))
+	})
+
+	It("should require the username of a GitLab deploy token", func() {
+		secret := map[string][]byte{"password": []byte("gldt-abcdef")}
+		_, err := getGitAuth(nil, "https://gitlab.com/group/repo.git", secret)
+		Expect(err).To(MatchError(ContainSubstring("GitLab deploy tokens need the username")))
+
+		secret["username"] = []byte("gitlab+deploy-token-1")
+		auth, err := getGitAuth(nil, "https://gitlab.com/group/repo.git", secret)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(auth).To(Equal(&http.BasicAuth{Username: "gitlab+deploy-token-1", Password: "gldt-abcdef"}))
+	})
+
+	It("should reject a client certificate without credentials", func() {
+		_, err := getGitAuth(nil, "https://git.example.com/repo.git", map[string][]byte{
+			"tlsClientCertData": []byte("cert"),
+			"tlsClientCertKey":  []byte("key"),
+		})
+		Expect(err).To(MatchError(ContainSubstring("tlsClientCertData needs a password")))
+	})
+})
+
+var _ = Describe("Azure DevOps", func() {
+	DescribeTable("detecting the repository URLs",
+		func(url string, expected bool) {
+			Expect(isAzureDevOpsURL(url)).To(Equal(expected))
+		},
+		Entry("https", "https://dev.azure.com/org/project/_git/repo", true),
+		Entry("ssh", "git@ssh.dev.azure.com:v3/org/project/repo", true),
+		Entry("visualstudio.com", "https://org.visualstudio.com/project/_git/repo", true),
+		Entry("github", "https://github.com/org/repo", false),
+		Entry("invalid", "not a url", false),
+	)
+
+	It("should only negotiate multi_ack with Azure DevOps", func() {
+		defer setGitCapabilities("")
+		setGitCapabilities("https://dev.azure.com/org/project/_git/repo")
+		Expect(transport.UnsupportedCapabilities).ToNot(ContainElement(capability.MultiACK))
+		setGitCapabilities("https://github.com/org/repo")
+		Expect(transport.UnsupportedCapabilities).To(ContainElement(capability.MultiACK))
+	})
+})
+
 var _ = Describe("getSshPublicKey", func() {
 	Context("with valid SSH key", func() {
 		It("should return public keys", func() {
@@ -394,12 +479,22 @@ var _ = Describe("getSshPublicKey", func() {
 				"-----END OPENSSH PRIVATE KEY-----\n")
 
 			secret := map[string][]byte{
-				"sshPrivateKey": testKey,
+				"sshPrivateKey":          testKey,
+				secretFieldSSHKnownHosts: []byte("github.com ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIOMqqnkVzrm0SdG6UOoqKLsabgH5C9okWi0dh2l9GKJl"),
 			}
 			publicKey, err := getSshPublicKey("git@github.com:user/repo", secret)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(publicKey).ToNot(BeNil())
 			Expect(publicKey.User).To(Equal("git"))
+			Expect(publicKey.HostKeyCallback).ToNot(BeNil())
+
+			delete(secret, secretFieldSSHKnownHosts)
+			_, err = getSshPublicKey("git@github.com:user/repo", secret)
+			Expect(err).To(MatchError(ContainSubstring("no SSH known hosts")))
+
+			secret[secretFieldInsecure] = []byte("true")
+			_, err = getSshPublicKey("git@github.com:user/repo", secret)
+			Expect(err).ToNot(HaveOccurred())
 		})
 	})
 
@@ -436,7 +531,7 @@ var _ = Describe("getGitRemoteURL", func() {
 
 	Context("when repository exists with a remote", func() {
 		It("should return the remote URL", func() {
-			err := cloneRepo(nil, gitOpsImpl, gitRepoURL, tempDir2, nil)
+			err := cloneRepo(nil, gitOpsImpl, gitRepoURL, tempDir2, nil, nil)
 			Expect(err).ToNot(HaveOccurred())
 
 			url, err := getGitRemoteURL(tempDir2, "origin")
@@ -518,3 +613,18 @@ var _ = Describe("getCommitFromTarget", func() {
 		Expect(err.Error()).To(ContainSubstring("unknown target"))
 	})
 })
+
+var _ = Describe("newPatternRevision", func() {
+	It("should describe the commit with its author and the first line of its message", func() {
+		commit := &object.Commit{
+			Hash:    plumbing.NewHash(gitCommitHash),
+			Author:  object.Signature{Name: "Jane Doe", Email: "jane@example.com", When: time.Now()},
+			Message: "Bump chart versions\n\nLonger description of the change\n",
+		}
+		revision := newPatternRevision(commit)
+		Expect(revision.Revision).To(Equal(gitCommitHash))
+		Expect(revision.Author).To(Equal("Jane Doe <jane@example.com>"))
+		Expect(revision.Message).To(Equal("Bump chart versions"))
+		Expect(revision.DeployedAt.IsZero()).To(BeFalse())
+	})
+})
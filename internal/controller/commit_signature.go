@@ -0,0 +1,281 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	stdssh "golang.org/x/crypto/ssh"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/hybrid-cloud-patterns/patterns-operator/api/v1alpha1"
+)
+
+const (
+	sshSignatureArmorStart = "-----BEGIN SSH SIGNATURE-----"
+	sshSignatureArmorEnd   = "-----END SSH SIGNATURE-----"
+	sshSignatureMagic      = "SSHSIG"
+	// Namespace git signs commits and tags in, see ssh-keygen -Y sign
+	sshSignatureNamespace = "git"
+)
+
+var pgpPublicKeyBlock = regexp.MustCompile(`(?s)-----BEGIN PGP PUBLIC KEY BLOCK-----.*?-----END PGP PUBLIC KEY BLOCK-----`)
+
+// signingKeys are the keys trusted to sign the target revision of a pattern
+type signingKeys struct {
+	// Armored GPG public keys, kept apart as go-git only reads the first armored block of a keyring
+	pgp []string
+	ssh []stdssh.PublicKey
+}
+
+// signatureVerificationError is returned when the target revision is not signed by a trusted key
+type signatureVerificationError struct {
+	msg string
+}
+
+func (e *signatureVerificationError) Error() string {
+	return e.msg
+}
+
+func isSignatureVerificationError(err error) bool {
+	var sigErr *signatureVerificationError
+	return errors.As(err, &sigErr)
+}
+
+// sshSignature is the blob of an armored SSH signature, see PROTOCOL.sshsig of OpenSSH
+type sshSignature struct {
+	Magic         [6]byte
+	Version       uint32
+	PublicKey     []byte
+	Namespace     string
+	Reserved      string
+	HashAlgorithm string
+	Signature     []byte
+}
+
+// sshSignedData is what the SSH signature is computed over
+type sshSignedData struct {
+	Magic         [6]byte
+	Namespace     string
+	Reserved      string
+	HashAlgorithm string
+	Hash          []byte
+}
+
+// getSigningKeys collects the keys of spec.gitSpec.signingKeysFrom and of every entry of the ConfigMap named in the
+// operator config. Returns nil when neither is configured, signatures are then not verified
+func (r *PatternReconciler) getSigningKeys(p *api.Pattern, patternsOperatorConfig PatternsOperatorConfig) (*signingKeys, error) {
+	var sources []string
+	configured := false
+	if source := p.Spec.GitConfig.SigningKeysFrom; source != nil {
+		configured = true
+		value, found, err := getParameterSourceValue(r.fullClient, p.Namespace, source)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			sources = append(sources, value)
+		}
+	}
+	if ref := patternsOperatorConfig.getStringValue(configKeySigningKeysConfigMap); ref != "" {
+		configured = true
+		namespace, name, found := strings.Cut(ref, "/")
+		if !found {
+			namespace, name = DetectOperatorNamespace(), ref
+		}
+		// Unlike the known hosts a missing ConfigMap is an error, deploying unverified revisions is not a safe fallback
+		cm, err := r.fullClient.CoreV1().ConfigMaps(namespace).Get(context.Background(), name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("could not read the signing keys of %s: %w", configKeySigningKeysConfigMap, err)
+		}
+		for _, key := range slices.Sorted(maps.Keys(cm.Data)) {
+			sources = append(sources, cm.Data[key])
+		}
+	}
+	if !configured {
+		return nil, nil
+	}
+	return parseSigningKeys(strings.Join(sources, "\n"))
+}
+
+// parseSigningKeys reads armored GPG public keys and SSH public keys, one per line in authorized_keys or
+// allowed_signers format
+func parseSigningKeys(data string) (*signingKeys, error) {
+	keys := &signingKeys{pgp: pgpPublicKeyBlock.FindAllString(data, -1)}
+	for line := range strings.SplitSeq(pgpPublicKeyBlock.ReplaceAllString(data, ""), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, err := parseSSHSigningKey(line)
+		if err != nil {
+			return nil, err
+		}
+		keys.ssh = append(keys.ssh, key)
+	}
+	if len(keys.pgp) == 0 && len(keys.ssh) == 0 {
+		return nil, fmt.Errorf("no GPG or SSH public keys found in the signing keys")
+	}
+	return keys, nil
+}
+
+// parseSSHSigningKey also accepts allowed_signers lines, where the principals and options precede the key
+func parseSSHSigningKey(line string) (stdssh.PublicKey, error) {
+	fields := strings.Fields(line)
+	for i := range fields {
+		if key, _, _, _, err := stdssh.ParseAuthorizedKey([]byte(strings.Join(fields[i:], " "))); err == nil {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("could not parse %q as a GPG or SSH public key", line)
+}
+
+// verifyRevisionSignature checks that the commit the revision was checked out at is signed by a trusted key.
+// When the revision names an annotated tag of that commit, a trusted signature of the tag is enough
+func verifyRevisionSignature(directory, revision string, commit *object.Commit, keys *signingKeys) error {
+	commitErr := keys.verifyCommit(commit)
+	if commitErr == nil {
+		return nil
+	}
+	if tag := getAnnotatedTag(directory, revision); tag != nil && tag.Target == commit.Hash {
+		tagErr := keys.verifyTag(tag)
+		if tagErr == nil {
+			return nil
+		}
+		return &signatureVerificationError{msg: fmt.Sprintf("neither commit %s (%v) nor tag %s (%v) is signed by a trusted key",
+			commit.Hash, commitErr, tag.Name, tagErr)}
+	}
+	return &signatureVerificationError{msg: fmt.Sprintf("commit %s is not signed by a trusted key: %v", commit.Hash, commitErr)}
+}
+
+// getAnnotatedTag returns the annotated tag named revision, nil for branches, commits and lightweight tags
+func getAnnotatedTag(directory, revision string) *object.Tag {
+	repo, err := git.PlainOpen(directory)
+	if err != nil {
+		return nil
+	}
+	ref, err := repo.Reference(plumbing.NewTagReferenceName(revision), true)
+	if err != nil {
+		return nil
+	}
+	tag, err := repo.TagObject(ref.Hash())
+	if err != nil {
+		return nil
+	}
+	return tag
+}
+
+func (k *signingKeys) verifyCommit(c *object.Commit) error {
+	return k.verify(c.PGPSignature, c.EncodeWithoutSignature, func(armoredKey string) error {
+		_, err := c.Verify(armoredKey)
+		return err
+	})
+}
+
+func (k *signingKeys) verifyTag(t *object.Tag) error {
+	return k.verify(t.PGPSignature, t.EncodeWithoutSignature, func(armoredKey string) error {
+		_, err := t.Verify(armoredKey)
+		return err
+	})
+}
+
+// verify checks an SSH signature against the SSH keys and any other signature against the GPG keys
+func (k *signingKeys) verify(signature string, encode func(plumbing.EncodedObject) error, verifyPGP func(string) error) error {
+	signature = strings.TrimSpace(signature)
+	if signature == "" {
+		return errors.New("not signed")
+	}
+	if strings.HasPrefix(signature, sshSignatureArmorStart) {
+		payload := &plumbing.MemoryObject{}
+		if err := encode(payload); err != nil {
+			return err
+		}
+		reader, err := payload.Reader()
+		if err != nil {
+			return err
+		}
+		message, err := io.ReadAll(reader)
+		if err != nil {
+			return err
+		}
+		return k.verifySSH(signature, message)
+	}
+
+	err := errors.New("no GPG keys are trusted")
+	for _, armoredKey := range k.pgp {
+		if err = verifyPGP(armoredKey); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+func (k *signingKeys) verifySSH(armored string, message []byte) error {
+	body := strings.TrimSuffix(strings.TrimPrefix(armored, sshSignatureArmorStart), sshSignatureArmorEnd)
+	raw, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(body), ""))
+	if err != nil {
+		return fmt.Errorf("invalid SSH signature: %w", err)
+	}
+	var sig sshSignature
+	if err := stdssh.Unmarshal(raw, &sig); err != nil {
+		return fmt.Errorf("invalid SSH signature: %w", err)
+	}
+	if string(sig.Magic[:]) != sshSignatureMagic || sig.Version != 1 {
+		return errors.New("invalid SSH signature: unsupported format")
+	}
+	if sig.Namespace != sshSignatureNamespace {
+		return fmt.Errorf("SSH signature is for namespace %q instead of %q", sig.Namespace, sshSignatureNamespace)
+	}
+	pub, err := stdssh.ParsePublicKey(sig.PublicKey)
+	if err != nil {
+		return fmt.Errorf("invalid SSH signature: %w", err)
+	}
+	if !k.trustsSSHKey(pub) {
+		return fmt.Errorf("SSH signature is made by untrusted key %s", stdssh.FingerprintSHA256(pub))
+	}
+
+	var h hash.Hash
+	switch sig.HashAlgorithm {
+	case "sha256":
+		h = sha256.New()
+	case "sha512":
+		h = sha512.New()
+	default:
+		return fmt.Errorf("unsupported SSH signature hash algorithm %q", sig.HashAlgorithm)
+	}
+	h.Write(message)
+	var blob stdssh.Signature
+	if err := stdssh.Unmarshal(sig.Signature, &blob); err != nil {
+		return fmt.Errorf("invalid SSH signature: %w", err)
+	}
+	signed := stdssh.Marshal(sshSignedData{
+		Magic:         sig.Magic,
+		Namespace:     sig.Namespace,
+		Reserved:      sig.Reserved,
+		HashAlgorithm: sig.HashAlgorithm,
+		Hash:          h.Sum(nil),
+	})
+	return pub.Verify(signed, &blob)
+}
+
+func (k *signingKeys) trustsSSHKey(pub stdssh.PublicKey) bool {
+	for _, key := range k.ssh {
+		if bytes.Equal(key.Marshal(), pub.Marshal()) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,211 @@
+package controllers
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/base64"
+	"io"
+	"os"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	stdssh "golang.org/x/crypto/ssh"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	api "github.com/hybrid-cloud-patterns/patterns-operator/api/v1alpha1"
+)
+
+// testSSHSigner signs like git with gpg.format=ssh
+type testSSHSigner struct {
+	signer    stdssh.Signer
+	namespace string
+}
+
+func (s testSSHSigner) Sign(message io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(message)
+	if err != nil {
+		return nil, err
+	}
+	digest := sha512.Sum512(data)
+	magic := [6]byte{}
+	copy(magic[:], sshSignatureMagic)
+	sig, err := s.signer.Sign(rand.Reader, stdssh.Marshal(sshSignedData{
+		Magic: magic, Namespace: s.namespace, HashAlgorithm: "sha512", Hash: digest[:],
+	}))
+	if err != nil {
+		return nil, err
+	}
+	blob := stdssh.Marshal(sshSignature{
+		Magic: magic, Version: 1, PublicKey: s.signer.PublicKey().Marshal(), Namespace: s.namespace,
+		HashAlgorithm: "sha512", Signature: stdssh.Marshal(sig),
+	})
+	return []byte(sshSignatureArmorStart + "\n" + base64.StdEncoding.EncodeToString(blob) + "\n" + sshSignatureArmorEnd + "\n"), nil
+}
+
+func newTestSSHSigner() stdssh.Signer {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	Expect(err).ToNot(HaveOccurred())
+	signer, err := stdssh.NewSignerFromKey(key)
+	Expect(err).ToNot(HaveOccurred())
+	return signer
+}
+
+func authorizedKey(signer stdssh.Signer) string {
+	return string(stdssh.MarshalAuthorizedKey(signer.PublicKey()))
+}
+
+var _ = Describe("commit signature verification", func() {
+	var repoDir string
+	var repo *git.Repository
+	var trusted stdssh.Signer
+	var keys *signingKeys
+
+	author := &object.Signature{Name: "Test Author", Email: "test@example.com", When: time.Now()}
+
+	commitWith := func(signer git.Signer) *object.Commit {
+		worktree, err := repo.Worktree()
+		Expect(err).ToNot(HaveOccurred())
+		h, err := worktree.Commit("commit", &git.CommitOptions{Author: author, AllowEmptyCommits: true, Signer: signer})
+		Expect(err).ToNot(HaveOccurred())
+		commit, err := repo.CommitObject(h)
+		Expect(err).ToNot(HaveOccurred())
+		return commit
+	}
+
+	BeforeEach(func() {
+		var err error
+		repoDir, err = os.MkdirTemp("", "signature-test")
+		Expect(err).ToNot(HaveOccurred())
+		repo, err = git.PlainInit(repoDir, false)
+		Expect(err).ToNot(HaveOccurred())
+		trusted = newTestSSHSigner()
+		keys, err = parseSigningKeys(authorizedKey(trusted))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(repoDir)).To(Succeed())
+	})
+
+	It("should accept a commit signed by a trusted SSH key", func() {
+		commit := commitWith(testSSHSigner{signer: trusted, namespace: sshSignatureNamespace})
+		Expect(verifyRevisionSignature(repoDir, "main", commit, keys)).To(Succeed())
+	})
+
+	It("should reject unsigned commits and commits signed by other keys", func() {
+		err := verifyRevisionSignature(repoDir, "main", commitWith(nil), keys)
+		Expect(isSignatureVerificationError(err)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("not signed"))
+
+		other := newTestSSHSigner()
+		err = verifyRevisionSignature(repoDir, "main", commitWith(testSSHSigner{signer: other, namespace: sshSignatureNamespace}), keys)
+		Expect(isSignatureVerificationError(err)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring(stdssh.FingerprintSHA256(other.PublicKey())))
+	})
+
+	It("should reject SSH signatures made for another namespace", func() {
+		err := verifyRevisionSignature(repoDir, "main", commitWith(testSSHSigner{signer: trusted, namespace: "file"}), keys)
+		Expect(isSignatureVerificationError(err)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring(`namespace "file"`))
+	})
+
+	It("should reject a commit whose content does not match its signature", func() {
+		commit := commitWith(testSSHSigner{signer: trusted, namespace: sshSignatureNamespace})
+		commit.Message = "tampered"
+		Expect(isSignatureVerificationError(verifyRevisionSignature(repoDir, "main", commit, keys))).To(BeTrue())
+	})
+
+	It("should accept an unsigned commit through a signed annotated tag of the target revision", func() {
+		commit := commitWith(nil)
+		tag := &object.Tag{Name: "v1.0", Tagger: *author, Message: "release\n", TargetType: plumbing.CommitObject, Target: commit.Hash}
+		payload := &plumbing.MemoryObject{}
+		Expect(tag.Encode(payload)).To(Succeed())
+		reader, err := payload.Reader()
+		Expect(err).ToNot(HaveOccurred())
+		signature, err := testSSHSigner{signer: trusted, namespace: sshSignatureNamespace}.Sign(reader)
+		Expect(err).ToNot(HaveOccurred())
+		tag.PGPSignature = string(signature)
+
+		signed := repo.Storer.NewEncodedObject()
+		Expect(tag.Encode(signed)).To(Succeed())
+		tagHash, err := repo.Storer.SetEncodedObject(signed)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(repo.Storer.SetReference(plumbing.NewHashReference(plumbing.NewTagReferenceName("v1.0"), tagHash))).To(Succeed())
+
+		Expect(verifyRevisionSignature(repoDir, "v1.0", commit, keys)).To(Succeed())
+		// The tag only vouches for the commit when it is the target revision
+		Expect(isSignatureVerificationError(verifyRevisionSignature(repoDir, "main", commit, keys))).To(BeTrue())
+	})
+})
+
+var _ = Describe("parseSigningKeys", func() {
+	It("should read GPG key blocks and SSH keys in authorized_keys and allowed_signers format", func() {
+		first, second := newTestSSHSigner(), newTestSSHSigner()
+		keys, err := parseSigningKeys("# release keys\n" +
+			"-----BEGIN PGP PUBLIC KEY BLOCK-----\n\nmDMEZ\n=abcd\n-----END PGP PUBLIC KEY BLOCK-----\n" +
+			authorizedKey(first) +
+			`dev@example.com,ops@example.com namespaces="git" ` + authorizedKey(second))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(keys.pgp).To(HaveLen(1))
+		Expect(keys.ssh).To(HaveLen(2))
+		Expect(keys.trustsSSHKey(first.PublicKey())).To(BeTrue())
+		Expect(keys.trustsSSHKey(second.PublicKey())).To(BeTrue())
+	})
+
+	It("should fail on lines that are not keys and when there are no keys", func() {
+		_, err := parseSigningKeys("not a key")
+		Expect(err).To(HaveOccurred())
+		_, err = parseSigningKeys("# only a comment\n")
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("getSigningKeys", func() {
+	var reconciler *PatternReconciler
+	var pattern *api.Pattern
+	var patternKey, configKey stdssh.Signer
+
+	BeforeEach(func() {
+		patternKey, configKey = newTestSSHSigner(), newTestSSHSigner()
+		reconciler = newFakeReconciler()
+		reconciler.fullClient = kubefake.NewSimpleClientset(
+			&v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "signing-keys", Namespace: "default"},
+				Data:       map[string]string{"keys": authorizedKey(patternKey)},
+			},
+			&v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "argocd-gpg-keys-cm", Namespace: "openshift-gitops"},
+				Data:       map[string]string{"release": authorizedKey(configKey)},
+			},
+		)
+		pattern = &api.Pattern{ObjectMeta: metav1.ObjectMeta{Name: "pattern", Namespace: "default"}}
+	})
+
+	It("should not verify signatures when no keys are configured", func() {
+		keys, err := reconciler.getSigningKeys(pattern, PatternsOperatorConfig{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(keys).To(BeNil())
+	})
+
+	It("should trust the keys of the pattern and of the operator config", func() {
+		pattern.Spec.GitConfig.SigningKeysFrom = &api.PatternParameterSource{ConfigMapKeyRef: &v1.ConfigMapKeySelector{
+			LocalObjectReference: v1.LocalObjectReference{Name: "signing-keys"}, Key: "keys",
+		}}
+		keys, err := reconciler.getSigningKeys(pattern, PatternsOperatorConfig{configKeySigningKeysConfigMap: "openshift-gitops/argocd-gpg-keys-cm"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(keys.trustsSSHKey(patternKey.PublicKey())).To(BeTrue())
+		Expect(keys.trustsSSHKey(configKey.PublicKey())).To(BeTrue())
+	})
+
+	It("should fail when the ConfigMap of the operator config does not exist", func() {
+		_, err := reconciler.getSigningKeys(pattern, PatternsOperatorConfig{configKeySigningKeysConfigMap: "openshift-gitops/missing"})
+		Expect(err).To(HaveOccurred())
+	})
+})
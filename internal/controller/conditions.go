@@ -0,0 +1,121 @@
+package controllers
+
+import (
+	"fmt"
+
+	argoapi "github.com/argoproj/argo-cd/v3/pkg/apis/application/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	api "github.com/hybrid-cloud-patterns/patterns-operator/api/v1alpha1"
+)
+
+// readyConditionTypes are the subsystem conditions that must all be True for the pattern to be Ready
+var readyConditionTypes = []api.PatternConditionType{
+	api.GitOpsOperatorReady,
+	api.ArgoCDReady,
+	api.GitCheckoutReady,
+	api.ApplicationSynced,
+	api.ApplicationHealthy,
+}
+
+// subsystemActionPerformed marks the condition of the subsystem a reconcile step belongs to as not ready
+// before recording the step. Steps that changed something are Progressing until the next loop confirms them
+func (r *PatternReconciler) subsystemActionPerformed(p *api.Pattern, conditionType api.PatternConditionType, reason string, err error) (reconcile.Result, error) {
+	if isWaitingError(err) {
+		setPatternCondition(p, conditionType, metav1.ConditionFalse, api.ReasonProgressing, fmt.Sprintf("%s: %s", reason, err.Error()))
+	} else if err != nil {
+		setPatternCondition(p, conditionType, metav1.ConditionFalse, api.ReasonReconcileFailed, fmt.Sprintf("%s: %s", reason, err.Error()))
+	} else {
+		setPatternCondition(p, conditionType, metav1.ConditionFalse, api.ReasonProgressing, reason)
+	}
+	return r.actionPerformed(p, reason, err)
+}
+
+// updateStepConditions keeps Ready in line with the outcome of a reconcile step that did not complete the loop.
+// A step that made a change does not flip a Ready pattern, it becomes not ready once a subsystem reports it
+func updateStepConditions(p *api.Pattern, reason string, err error) {
+	switch {
+	case !p.DeletionTimestamp.IsZero():
+		updateDeletionCondition(p)
+	case isWaitingError(err):
+		if !isPatternSuspended(p) && !isConditionTrue(p, api.Ready) {
+			setPatternCondition(p, api.Ready, metav1.ConditionFalse, api.ReasonProgressing, fmt.Sprintf("%s: %s", reason, err.Error()))
+		}
+	case err != nil:
+		setPatternCondition(p, api.Ready, metav1.ConditionFalse, api.ReasonReconcileFailed, fmt.Sprintf("%s: %s", reason, err.Error()))
+	case !isPatternSuspended(p) && !isConditionTrue(p, api.Ready):
+		setPatternCondition(p, api.Ready, metav1.ConditionFalse, api.ReasonProgressing, reason)
+	}
+}
+
+// updateDeletionCondition reports the deletion phase of a pattern that is being deleted
+func updateDeletionCondition(p *api.Pattern) bool {
+	reason := api.ReasonDeleting
+	if p.Status.DeletionPhase != api.InitializeDeletion {
+		reason = string(p.Status.DeletionPhase)
+	}
+	changed := setPatternCondition(p, api.DeletionInProgress, metav1.ConditionTrue, reason,
+		fmt.Sprintf("Deletion phase %q", reason))
+	return setPatternCondition(p, api.Ready, metav1.ConditionFalse, api.ReasonDeleting, "The pattern is being deleted") || changed
+}
+
+// updateApplicationConditions reports the sync and health status of the clustergroup application,
+// app is nil when it does not exist. Returns true if the conditions changed
+func updateApplicationConditions(p *api.Pattern, app *argoapi.Application) bool {
+	if app == nil {
+		message := fmt.Sprintf("Application %s does not exist", applicationName(p))
+		changed := setPatternCondition(p, api.ApplicationSynced, metav1.ConditionFalse, api.ReasonApplicationMissing, message)
+		return setPatternCondition(p, api.ApplicationHealthy, metav1.ConditionFalse, api.ReasonApplicationMissing, message) || changed
+	}
+
+	syncStatus := string(app.Status.Sync.Status)
+	if syncStatus == "" {
+		syncStatus = string(argoapi.SyncStatusCodeUnknown)
+	}
+	changed := setPatternCondition(p, api.ApplicationSynced, conditionStatus(syncStatus == string(argoapi.SyncStatusCodeSynced)),
+		syncStatus, fmt.Sprintf("Application %s is %s", app.Name, syncStatus))
+
+	healthStatus := string(app.Status.Health.Status)
+	if healthStatus == "" {
+		healthStatus = "Unknown"
+	}
+	message := fmt.Sprintf("Application %s is %s", app.Name, healthStatus)
+	if app.Status.Health.Message != "" {
+		message += ": " + app.Status.Health.Message
+	}
+	return setPatternCondition(p, api.ApplicationHealthy, conditionStatus(healthStatus == "Healthy"),
+		healthStatus, message) || changed
+}
+
+// updateReadyCondition sets Ready once the loop completed, True when every subsystem condition is True and
+// otherwise False with the reason of the first one that is not. Also records the generation that was reconciled.
+// Returns true if the status changed
+func updateReadyCondition(p *api.Pattern) bool {
+	changed := p.Status.ObservedGeneration != p.Generation
+	p.Status.ObservedGeneration = p.Generation
+	for _, conditionType := range readyConditionTypes {
+		_, c := getPatternConditionByType(p.Status.Conditions, conditionType)
+		if c == nil {
+			return setPatternCondition(p, api.Ready, metav1.ConditionUnknown, api.ReasonProgressing,
+				fmt.Sprintf("%s has not been reported yet", conditionType)) || changed
+		}
+		if c.Status != metav1.ConditionTrue {
+			return setPatternCondition(p, api.Ready, metav1.ConditionFalse, c.Reason,
+				fmt.Sprintf("%s: %s", conditionType, c.Message)) || changed
+		}
+	}
+	return setPatternCondition(p, api.Ready, metav1.ConditionTrue, api.ReasonReconciled, "All subsystems are ready") || changed
+}
+
+func isConditionTrue(p *api.Pattern, conditionType api.PatternConditionType) bool {
+	_, c := getPatternConditionByType(p.Status.Conditions, conditionType)
+	return c != nil && c.Status == metav1.ConditionTrue
+}
+
+func conditionStatus(ok bool) metav1.ConditionStatus {
+	if ok {
+		return metav1.ConditionTrue
+	}
+	return metav1.ConditionFalse
+}
@@ -0,0 +1,146 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	argoapi "github.com/argoproj/argo-cd/v3/pkg/apis/application/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	api "github.com/hybrid-cloud-patterns/patterns-operator/api/v1alpha1"
+)
+
+func expectCondition(p *api.Pattern, conditionType api.PatternConditionType, status metav1.ConditionStatus, reason string) *metav1.Condition {
+	_, c := getPatternConditionByType(p.Status.Conditions, conditionType)
+	ExpectWithOffset(1, c).ToNot(BeNil(), "condition %s", conditionType)
+	ExpectWithOffset(1, c.Status).To(Equal(status), "status of %s", conditionType)
+	ExpectWithOffset(1, c.Reason).To(Equal(reason), "reason of %s", conditionType)
+	return c
+}
+
+var _ = Describe("pattern conditions", func() {
+	var pattern *api.Pattern
+
+	BeforeEach(func() {
+		pattern = buildPatternManifest()
+		pattern.Generation = 4
+	})
+
+	Context("Ready", func() {
+		It("should be unknown until every subsystem reported", func() {
+			setPatternCondition(pattern, api.GitOpsOperatorReady, metav1.ConditionTrue, api.ReasonReconciled, "")
+			Expect(updateReadyCondition(pattern)).To(BeTrue())
+			c := expectCondition(pattern, api.Ready, metav1.ConditionUnknown, api.ReasonProgressing)
+			Expect(c.Message).To(ContainSubstring(string(api.ArgoCDReady)))
+		})
+
+		It("should take the reason of the first subsystem that is not ready", func() {
+			for _, conditionType := range readyConditionTypes {
+				setPatternCondition(pattern, conditionType, metav1.ConditionTrue, api.ReasonReconciled, "")
+			}
+			setPatternCondition(pattern, api.ApplicationHealthy, metav1.ConditionFalse, "Degraded", "Application is Degraded")
+			updateReadyCondition(pattern)
+			c := expectCondition(pattern, api.Ready, metav1.ConditionFalse, "Degraded")
+			Expect(c.Message).To(Equal("ApplicationHealthy: Application is Degraded"))
+		})
+
+		It("should be true with the reconciled generation once every subsystem is ready", func() {
+			for _, conditionType := range readyConditionTypes {
+				setPatternCondition(pattern, conditionType, metav1.ConditionTrue, api.ReasonReconciled, "")
+			}
+			Expect(updateReadyCondition(pattern)).To(BeTrue())
+			c := expectCondition(pattern, api.Ready, metav1.ConditionTrue, api.ReasonReconciled)
+			Expect(c.ObservedGeneration).To(Equal(int64(4)))
+			Expect(pattern.Status.ObservedGeneration).To(Equal(int64(4)))
+			Expect(updateReadyCondition(pattern)).To(BeFalse())
+		})
+
+		It("should be false after a failed step but stay true after a step that made a change", func() {
+			setPatternCondition(pattern, api.Ready, metav1.ConditionTrue, api.ReasonReconciled, "")
+			updateStepConditions(pattern, "updated application", nil)
+			expectCondition(pattern, api.Ready, metav1.ConditionTrue, api.ReasonReconciled)
+
+			updateStepConditions(pattern, "validation", fmt.Errorf("boom"))
+			c := expectCondition(pattern, api.Ready, metav1.ConditionFalse, api.ReasonReconcileFailed)
+			Expect(c.Message).To(Equal("validation: boom"))
+
+			updateStepConditions(pattern, "create application", nil)
+			c = expectCondition(pattern, api.Ready, metav1.ConditionFalse, api.ReasonProgressing)
+			Expect(c.Message).To(Equal("create application"))
+		})
+	})
+
+	Context("application", func() {
+		It("should report a missing application", func() {
+			Expect(updateApplicationConditions(pattern, nil)).To(BeTrue())
+			expectCondition(pattern, api.ApplicationSynced, metav1.ConditionFalse, api.ReasonApplicationMissing)
+			expectCondition(pattern, api.ApplicationHealthy, metav1.ConditionFalse, api.ReasonApplicationMissing)
+		})
+
+		It("should use the sync and health status as reasons", func() {
+			app := &argoapi.Application{
+				ObjectMeta: metav1.ObjectMeta{Name: applicationName(pattern)},
+				Status: argoapi.ApplicationStatus{
+					Sync:   argoapi.SyncStatus{Status: argoapi.SyncStatusCodeOutOfSync},
+					Health: argoapi.AppHealthStatus{Status: "Degraded", Message: "deployment failed"},
+				},
+			}
+			Expect(updateApplicationConditions(pattern, app)).To(BeTrue())
+			expectCondition(pattern, api.ApplicationSynced, metav1.ConditionFalse, "OutOfSync")
+			c := expectCondition(pattern, api.ApplicationHealthy, metav1.ConditionFalse, "Degraded")
+			Expect(c.Message).To(ContainSubstring("deployment failed"))
+
+			app.Status.Sync.Status = argoapi.SyncStatusCodeSynced
+			app.Status.Health = argoapi.AppHealthStatus{Status: "Healthy"}
+			Expect(updateApplicationConditions(pattern, app)).To(BeTrue())
+			expectCondition(pattern, api.ApplicationSynced, metav1.ConditionTrue, "Synced")
+			expectCondition(pattern, api.ApplicationHealthy, metav1.ConditionTrue, "Healthy")
+			Expect(updateApplicationConditions(pattern, app)).To(BeFalse())
+		})
+	})
+
+	Context("deletion", func() {
+		It("should report the deletion phase", func() {
+			now := metav1.Now()
+			pattern.DeletionTimestamp = &now
+			updateStepConditions(pattern, "finalize", fmt.Errorf("initialized deletion phase, requeueing now"))
+			expectCondition(pattern, api.DeletionInProgress, metav1.ConditionTrue, api.ReasonDeleting)
+			expectCondition(pattern, api.Ready, metav1.ConditionFalse, api.ReasonDeleting)
+
+			pattern.Status.DeletionPhase = api.DeleteHubChildApps
+			Expect(updateDeletionCondition(pattern)).To(BeTrue())
+			expectCondition(pattern, api.DeletionInProgress, metav1.ConditionTrue, string(api.DeleteHubChildApps))
+		})
+	})
+
+	It("should mark the subsystem of a failed step as not ready", func() {
+		reconciler := newFakeReconciler()
+		reconciler.Client = fake.NewClientBuilder().WithScheme(scheme.Scheme).
+			WithObjects(pattern).WithStatusSubresource(&api.Pattern{}).Build()
+		current := &api.Pattern{}
+		Expect(reconciler.Client.Get(context.Background(), patternNamespaced, current)).To(Succeed())
+
+		_, err := reconciler.subsystemActionPerformed(current, api.ArgoCDReady, "error creating ArgoCD namespace", fmt.Errorf("forbidden"))
+		Expect(err).ToNot(HaveOccurred())
+
+		stored := &api.Pattern{}
+		Expect(reconciler.Client.Get(context.Background(), patternNamespaced, stored)).To(Succeed())
+		c := expectCondition(stored, api.ArgoCDReady, metav1.ConditionFalse, api.ReasonReconcileFailed)
+		Expect(c.Message).To(Equal("error creating ArgoCD namespace: forbidden"))
+		expectCondition(stored, api.Ready, metav1.ConditionFalse, api.ReasonReconcileFailed)
+	})
+
+	It("should drop conditions written without a reason", func() {
+		pattern.Status.Conditions = []metav1.Condition{
+			{Type: string(api.Missing), Status: metav1.ConditionTrue, LastTransitionTime: metav1.Now()},
+			{Type: string(api.GitInSync), Status: metav1.ConditionTrue, Reason: api.ReasonCommitSynced, LastTransitionTime: metav1.Now()},
+		}
+		dropLegacyConditions(pattern)
+		Expect(pattern.Status.Conditions).To(HaveLen(1))
+		Expect(pattern.Status.Conditions[0].Type).To(Equal(string(api.GitInSync)))
+	})
+})
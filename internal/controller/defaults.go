@@ -3,6 +3,8 @@ package controllers
 import (
 	"os"
 	"strings"
+
+	api "github.com/hybrid-cloud-patterns/patterns-operator/api/v1alpha1"
 )
 
 // DetectGitOpsSubscription returns the subscription name and namespace for the
@@ -40,6 +42,8 @@ const (
 	LegacyApplicationNamespace = "openshift-gitops"
 	// Legacy ClusterWide Argo Name
 	LegacyClusterWideArgoName = "openshift-gitops"
+	// Number of deployed revisions kept in the pattern status (see MaxItems on RevisionHistory)
+	RevisionHistoryLimit = 10
 )
 
 // GitOps Subscription
@@ -55,10 +59,25 @@ const (
 	GitOpsDefaultCSV = ""
 )
 
+// Upstream argocd-operator Subscription, from the OperatorHub.io catalog of upstream OLM
+const (
+	ArgoCDOperatorDefaultSubscriptionNamespace  = "argocd-operator"
+	ArgoCDOperatorDefaultChannel                = "alpha"
+	ArgoCDOperatorPackageName                   = "argocd-operator"
+	ArgoCDOperatorDefaultCatalogSource          = "operatorhubio-catalog"
+	ArgoCDOperatorDefaultCatalogSourceNamespace = "olm"
+)
+
+// Argo CD adopted with the external provisioner, where the upstream install manifests put it
+const (
+	ExternalArgoDefaultNamespace = "argocd"
+	ExternalArgoDefaultName      = "argocd"
+)
+
 // Gitea chart defaults
 const (
 	// URL to the Validated Patterns Helm chart repo
-	GiteaHelmRepoUrl = "https://charts.validatedpatterns.io/"
+	GiteaHelmRepoUrl = api.DefaultHelmRepoUrl
 	// Repo name for the Validated Patterns Helm repo
 	GiteaRepoName = "helm-charts"
 	// Gitea chart name in the Validated Patterns repo
@@ -0,0 +1,40 @@
+package controllers
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	api "github.com/hybrid-cloud-patterns/patterns-operator/api/v1alpha1"
+)
+
+// Reasons of the events recorded on a Pattern
+const (
+	EventReasonReconcileFailed      = "ReconcileFailed"
+	EventReasonSubscriptionCreated  = "SubscriptionCreated"
+	EventReasonSubscriptionUpdated  = "SubscriptionUpdated"
+	EventReasonArgoCDUpdated        = "ArgoCDUpdated"
+	EventReasonApplicationCreated   = "ApplicationCreated"
+	EventReasonApplicationUpdated   = "ApplicationUpdated"
+	EventReasonGiteaMigrated        = "GiteaMigrated"
+	EventReasonDeletionPhaseChanged = "DeletionPhaseChanged"
+	EventReasonSuspended            = "Suspended"
+	EventReasonResumed              = "Resumed"
+)
+
+// recordEvent records an event on the pattern so that `oc describe pattern` shows the reconcile timeline.
+// It is a no-op when the reconciler was created without a recorder
+func (r *PatternReconciler) recordEvent(p *api.Pattern, eventType, reason, messageFmt string, args ...any) {
+	if r.Recorder == nil {
+		return
+	}
+	r.Recorder.Eventf(p, eventType, reason, messageFmt, args...)
+}
+
+// recordNormalEvent records a Normal event on the pattern
+func (r *PatternReconciler) recordNormalEvent(p *api.Pattern, reason, messageFmt string, args ...any) {
+	r.recordEvent(p, corev1.EventTypeNormal, reason, messageFmt, args...)
+}
+
+// recordWarningEvent records a Warning event on the pattern
+func (r *PatternReconciler) recordWarningEvent(p *api.Pattern, reason, messageFmt string, args ...any) {
+	r.recordEvent(p, corev1.EventTypeWarning, reason, messageFmt, args...)
+}
@@ -0,0 +1,78 @@
+package controllers
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	nethttp "net/http"
+
+	"k8s.io/client-go/kubernetes"
+
+	api "github.com/hybrid-cloud-patterns/patterns-operator/api/v1alpha1"
+)
+
+// gitTLSConfig holds the TLS settings of the connections to an https git server
+type gitTLSConfig struct {
+	// PEM encoded CAs trusted on top of the cluster trusted CA bundle
+	caBundle []byte
+	// Set only by spec.gitSpec.insecureSkipTLSVerify
+	insecureSkipVerify bool
+	// From tlsClientCertData and tlsClientCertKey of the git auth secret
+	clientCertificates []tls.Certificate
+}
+
+// insecure is false for a nil config, verification is never skipped by default
+func (c *gitTLSConfig) insecure() bool {
+	return c != nil && c.insecureSkipVerify
+}
+
+// getGitTLSConfig resolves spec.gitSpec.caBundleFrom, spec.gitSpec.insecureSkipTLSVerify and the client
+// certificate of the git auth secret
+func getGitTLSConfig(fullClient kubernetes.Interface, p *api.Pattern, secret map[string][]byte) (*gitTLSConfig, error) {
+	tlsConfig := &gitTLSConfig{insecureSkipVerify: p.Spec.GitConfig.InsecureSkipTLSVerify}
+	certData, certKey := getField(secret, secretFieldTLSClientCertData), getField(secret, secretFieldTLSClientCertKey)
+	if certData != nil || certKey != nil {
+		cert, err := tls.X509KeyPair(certData, certKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s and %s in the token secret: %w", secretFieldTLSClientCertData, secretFieldTLSClientCertKey, err)
+		}
+		tlsConfig.clientCertificates = []tls.Certificate{cert}
+	}
+	if tlsConfig.insecureSkipVerify {
+		logOnce(fmt.Sprintf("TLS verification of the git server of pattern %s/%s is disabled by spec.gitSpec.insecureSkipTLSVerify",
+			p.Namespace, p.Name))
+	}
+	if p.Spec.GitConfig.CABundleFrom == nil {
+		return tlsConfig, nil
+	}
+	value, found, err := getParameterSourceValue(fullClient, p.Namespace, p.Spec.GitConfig.CABundleFrom)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return tlsConfig, nil
+	}
+	if !x509.NewCertPool().AppendCertsFromPEM([]byte(value)) {
+		return nil, fmt.Errorf("spec.gitSpec.caBundleFrom does not contain any PEM encoded certificate")
+	}
+	tlsConfig.caBundle = []byte(value)
+	return tlsConfig, nil
+}
+
+// getGitHTTPSTransport returns the transport of getHTTPSTransport, which trusts the cluster CAs, with the
+// CAs of the pattern added and presenting the client certificate of the git auth secret
+func getGitHTTPSTransport(fullClient kubernetes.Interface, tlsConfig *gitTLSConfig) *nethttp.Transport {
+	transport := getHTTPSTransport(fullClient)
+	if tlsConfig == nil {
+		return transport
+	}
+	transport.TLSClientConfig.Certificates = tlsConfig.clientCertificates
+	if len(tlsConfig.caBundle) == 0 {
+		return transport
+	}
+	if transport.TLSClientConfig.RootCAs == nil {
+		transport.TLSClientConfig.RootCAs = x509.NewCertPool()
+	}
+	transport.TLSClientConfig.RootCAs.AppendCertsFromPEM(tlsConfig.caBundle)
+	return transport
+}
@@ -0,0 +1,120 @@
+package controllers
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	api "github.com/hybrid-cloud-patterns/patterns-operator/api/v1alpha1"
+)
+
+// newTestCA returns a self-signed CA certificate with the PEM encoding of it and of its key
+func newTestCA() (cert *x509.Certificate, certPEM, keyPEM []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).ToNot(HaveOccurred())
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "git test CA"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	Expect(err).ToNot(HaveOccurred())
+	cert, err = x509.ParseCertificate(der)
+	Expect(err).ToNot(HaveOccurred())
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	Expect(err).ToNot(HaveOccurred())
+	return cert, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+var _ = Describe("git TLS verification", func() {
+	var clientset *kubefake.Clientset
+	var pattern *api.Pattern
+	var caCert *x509.Certificate
+	var caPEM []byte
+
+	BeforeEach(func() {
+		caCert, caPEM, _ = newTestCA()
+		clientset = kubefake.NewSimpleClientset(&v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "git-ca", Namespace: "default"},
+			Data:       map[string]string{"ca.crt": string(caPEM), "garbage": "not a certificate"},
+		})
+		pattern = &api.Pattern{ObjectMeta: metav1.ObjectMeta{Name: "pattern", Namespace: "default"}}
+	})
+
+	caBundleFrom := func(key string) *api.PatternParameterSource {
+		return &api.PatternParameterSource{
+			ConfigMapKeyRef: &v1.ConfigMapKeySelector{LocalObjectReference: v1.LocalObjectReference{Name: "git-ca"}, Key: key},
+		}
+	}
+
+	It("should verify certificates unless the pattern opts out", func() {
+		var nilConfig *gitTLSConfig
+		Expect(nilConfig.insecure()).To(BeFalse())
+
+		tlsConfig, err := getGitTLSConfig(clientset, pattern, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tlsConfig.insecure()).To(BeFalse())
+		fetchOptions, err := getFetchOptions(nil, "https://github.com/user/repo", nil, tlsConfig)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fetchOptions.InsecureSkipTLS).To(BeFalse())
+
+		pattern.Spec.GitConfig.InsecureSkipTLSVerify = true
+		tlsConfig, err = getGitTLSConfig(clientset, pattern, nil)
+		Expect(err).ToNot(HaveOccurred())
+		fetchOptions, err = getFetchOptions(nil, "https://github.com/user/repo", nil, tlsConfig)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fetchOptions.InsecureSkipTLS).To(BeTrue())
+		cloneOptions, err := getCloneOptions(nil, "https://github.com/user/repo", nil, tlsConfig)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cloneOptions.InsecureSkipTLS).To(BeTrue())
+	})
+
+	It("should trust the CA bundle of the pattern", func() {
+		pattern.Spec.GitConfig.CABundleFrom = caBundleFrom("ca.crt")
+		tlsConfig, err := getGitTLSConfig(clientset, pattern, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tlsConfig.caBundle).To(Equal(caPEM))
+
+		transport := getGitHTTPSTransport(nil, tlsConfig)
+		Expect(transport.TLSClientConfig.InsecureSkipVerify).To(BeFalse())
+		Expect(transport.TLSClientConfig.RootCAs.Subjects()).To(ContainElement(caCert.RawSubject)) //nolint:staticcheck
+	})
+
+	It("should present the client certificate of the git auth secret", func() {
+		_, certPEM, keyPEM := newTestCA()
+		secret := map[string][]byte{"password": []byte("token"), "tlsClientCertData": certPEM, "tlsClientCertKey": keyPEM}
+		tlsConfig, err := getGitTLSConfig(clientset, pattern, secret)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(getGitHTTPSTransport(nil, tlsConfig).TLSClientConfig.Certificates).To(HaveLen(1))
+
+		delete(secret, "tlsClientCertKey")
+		_, err = getGitTLSConfig(clientset, pattern, secret)
+		Expect(err).To(MatchError(ContainSubstring("invalid tlsClientCertData and tlsClientCertKey")))
+	})
+
+	It("should reject a CA bundle without certificates", func() {
+		pattern.Spec.GitConfig.CABundleFrom = caBundleFrom("garbage")
+		_, err := getGitTLSConfig(clientset, pattern, nil)
+		Expect(err).To(MatchError(ContainSubstring("does not contain any PEM encoded certificate")))
+
+		pattern.Spec.GitConfig.CABundleFrom = caBundleFrom("missing")
+		_, err = getGitTLSConfig(clientset, pattern, nil)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,163 @@
+package controllers
+
+import (
+	"fmt"
+
+	argoapi "github.com/argoproj/argo-cd/v3/pkg/apis/application/v1alpha1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	api "github.com/hybrid-cloud-patterns/patterns-operator/api/v1alpha1"
+)
+
+// Values of gitops.provisioner
+const (
+	// Red Hat OpenShift GitOps installed through OLM, the default
+	GitOpsProvisionerOpenShiftGitOps = "openshift-gitops"
+	// Upstream argocd-operator installed through OLM from a configurable catalog
+	GitOpsProvisionerArgoCDOperator = "argocd-operator"
+	// An Argo CD installed and managed by someone else, adopted by namespace and name
+	GitOpsProvisionerExternal = "external"
+)
+
+// GitOpsProvisioner provides the Argo CD instance the pattern applications are created in
+type GitOpsProvisioner interface {
+	// Name is the value of gitops.provisioner that selects the backend
+	Name() string
+	// DetectArgoInstance sets the namespace and name of the Argo CD instance returned by
+	// getClusterWideArgoNamespace and getClusterWideArgoName
+	DetectArgoInstance(r *PatternReconciler)
+	// Reconcile installs the GitOps operator, or checks that Argo CD is available.
+	// Returns (done, result, err) — when done is true the caller should return result/err immediately
+	Reconcile(r *PatternReconciler, p *api.Pattern) (done bool, result ctrl.Result, err error)
+	// ReadyMessage describes the outcome of the last successful Reconcile
+	ReadyMessage() string
+	// ManagesArgoCD is true when the operator creates and updates the ArgoCD instance, its namespace and ConsoleLink
+	ManagesArgoCD() bool
+}
+
+// newGitOpsProvisioner returns the backend selected by gitops.provisioner
+func newGitOpsProvisioner(patternsOperatorConfig PatternsOperatorConfig) (GitOpsProvisioner, error) {
+	switch name := patternsOperatorConfig.getStringValue(configKeyProvisioner); name {
+	case GitOpsProvisionerOpenShiftGitOps:
+		return &openShiftGitOpsProvisioner{config: patternsOperatorConfig}, nil
+	case GitOpsProvisionerArgoCDOperator:
+		return &argoCDOperatorProvisioner{config: patternsOperatorConfig}, nil
+	case GitOpsProvisionerExternal:
+		return &externalArgoProvisioner{
+			namespace: patternsOperatorConfig.getStringValue(configKeyExternalArgoNamespace),
+			name:      patternsOperatorConfig.getStringValue(configKeyExternalArgoName),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown %s %q, expected one of %s, %s or %s", configKeyProvisioner, name,
+			GitOpsProvisionerOpenShiftGitOps, GitOpsProvisionerArgoCDOperator, GitOpsProvisionerExternal)
+	}
+}
+
+type openShiftGitOpsProvisioner struct {
+	config    PatternsOperatorConfig
+	olmAbsent bool
+}
+
+func (o *openShiftGitOpsProvisioner) Name() string {
+	return GitOpsProvisionerOpenShiftGitOps
+}
+
+// DetectArgoInstance must run before the subscription is reconciled since it controls DISABLE_DEFAULT_ARGOCD_INSTANCE
+func (o *openShiftGitOpsProvisioner) DetectArgoInstance(r *PatternReconciler) {
+	detectArgoNamespace(r.dynamicClient)
+}
+
+func (o *openShiftGitOpsProvisioner) Reconcile(r *PatternReconciler, p *api.Pattern) (done bool, result ctrl.Result, err error) {
+	// Without OLM there is no subscription to manage, Argo CD is installed separately
+	haveOLM, err := haveAPIVersion(r.fullClient, OLMGroup, "v1alpha1")
+	if err != nil {
+		res, e := r.subsystemActionPerformed(p, api.GitOpsOperatorReady, "checking the OLM API", err)
+		return true, res, e
+	}
+	o.olmAbsent = !haveOLM
+	if o.olmAbsent {
+		logOnce(fmt.Sprintf("API group %s is not available, skipping the GitOps operator subscription", OLMGroup))
+		return false, ctrl.Result{}, nil
+	}
+	// Only disable the default ArgoCD instance for non-legacy deployments.
+	// For legacy deployments, the gitops-operator's default instance is still in use.
+	return r.reconcileGitOpsSubscription(p, newSubscription(o.config, !isLegacyArgoNamespace()))
+}
+
+func (o *openShiftGitOpsProvisioner) ReadyMessage() string {
+	if o.olmAbsent {
+		return "OLM is not available, Argo CD must be installed separately"
+	}
+	return "The GitOps operator subscription is up to date"
+}
+
+func (o *openShiftGitOpsProvisioner) ManagesArgoCD() bool {
+	return true
+}
+
+type argoCDOperatorProvisioner struct {
+	config PatternsOperatorConfig
+}
+
+func (a *argoCDOperatorProvisioner) Name() string {
+	return GitOpsProvisionerArgoCDOperator
+}
+
+// DetectArgoInstance always uses the vp-gitops instance, the legacy openshift-gitops one only exists with OpenShift GitOps
+func (a *argoCDOperatorProvisioner) DetectArgoInstance(r *PatternReconciler) { //nolint:revive
+	activeArgoNamespace = ApplicationNamespace
+	activeArgoName = ClusterWideArgoName
+}
+
+func (a *argoCDOperatorProvisioner) Reconcile(r *PatternReconciler, p *api.Pattern) (done bool, result ctrl.Result, err error) {
+	if err := checkAPIVersion(r.fullClient, OLMGroup, "v1alpha1"); err != nil {
+		res, e := r.subsystemActionPerformed(p, api.GitOpsOperatorReady, "checking the OLM API of the argocd-operator", err)
+		return true, res, e
+	}
+	return r.reconcileGitOpsSubscription(p, newArgoCDOperatorSubscription(a.config))
+}
+
+func (a *argoCDOperatorProvisioner) ReadyMessage() string {
+	return "The argocd-operator subscription is up to date"
+}
+
+func (a *argoCDOperatorProvisioner) ManagesArgoCD() bool {
+	return true
+}
+
+type externalArgoProvisioner struct {
+	namespace string
+	name      string
+}
+
+func (x *externalArgoProvisioner) Name() string {
+	return GitOpsProvisionerExternal
+}
+
+func (x *externalArgoProvisioner) DetectArgoInstance(r *PatternReconciler) { //nolint:revive
+	activeArgoNamespace = x.namespace
+	activeArgoName = x.name
+	logOnce(fmt.Sprintf("Using the externally managed Argo CD instance %s/%s", x.namespace, x.name))
+}
+
+// Reconcile only checks that Argo CD can receive the pattern applications, nothing is installed or updated
+func (x *externalArgoProvisioner) Reconcile(r *PatternReconciler, p *api.Pattern) (done bool, result ctrl.Result, err error) {
+	if err := checkAPIVersion(r.fullClient, ArgoCDGroup, argoapi.ApplicationSchemaGroupVersionKind.Version); err != nil {
+		res, e := r.subsystemActionPerformed(p, api.GitOpsOperatorReady, "waiting for the Argo CD application API", err)
+		return true, res, e
+	}
+	if !haveNamespace(r.Client, x.namespace) {
+		res, e := r.subsystemActionPerformed(p, api.GitOpsOperatorReady, "waiting for the Argo CD namespace",
+			fmt.Errorf("namespace %s of the external Argo CD instance does not exist", x.namespace))
+		return true, res, e
+	}
+	return false, ctrl.Result{}, nil
+}
+
+func (x *externalArgoProvisioner) ReadyMessage() string {
+	return fmt.Sprintf("Using the Argo CD instance %s/%s, it is not managed by the operator", x.namespace, x.name)
+}
+
+func (x *externalArgoProvisioner) ManagesArgoCD() bool {
+	return false
+}
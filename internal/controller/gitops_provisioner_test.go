@@ -0,0 +1,124 @@
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	discoveryfake "k8s.io/client-go/discovery/fake"
+	kubeclient "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	api "github.com/hybrid-cloud-patterns/patterns-operator/api/v1alpha1"
+)
+
+var _ = Describe("GitOps provisioner", func() {
+	var (
+		reconciler *PatternReconciler
+		pattern    *api.Pattern
+	)
+
+	serveAPIs := func(groupVersions ...string) {
+		resources := []*metav1.APIResourceList{}
+		for _, gv := range groupVersions {
+			resources = append(resources, &metav1.APIResourceList{GroupVersion: gv})
+		}
+		reconciler.fullClient.Discovery().(*discoveryfake.FakeDiscovery).Resources = resources
+	}
+
+	BeforeEach(func() {
+		reconciler = newFakeReconciler()
+		reconciler.Client = fake.NewClientBuilder().WithScheme(scheme.Scheme).
+			WithObjects(buildPatternManifest()).WithStatusSubresource(&api.Pattern{}).Build()
+		pattern = &api.Pattern{}
+		Expect(reconciler.Client.Get(context.Background(), patternNamespaced, pattern)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		activeArgoNamespace = ApplicationNamespace
+		activeArgoName = ClusterWideArgoName
+	})
+
+	It("should default to OpenShift GitOps and reject unknown backends", func() {
+		provisioner, err := newGitOpsProvisioner(nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(provisioner.Name()).To(Equal(GitOpsProvisionerOpenShiftGitOps))
+		Expect(provisioner.ManagesArgoCD()).To(BeTrue())
+
+		_, err = newGitOpsProvisioner(PatternsOperatorConfig{configKeyProvisioner: "flux"})
+		Expect(err).To(MatchError(ContainSubstring(`unknown gitops.provisioner "flux"`)))
+	})
+
+	It("should skip the subscription when OLM is not available", func() {
+		provisioner, _ := newGitOpsProvisioner(nil)
+		done, _, err := provisioner.Reconcile(reconciler, pattern)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(done).To(BeFalse())
+		Expect(provisioner.ReadyMessage()).To(ContainSubstring("OLM is not available"))
+	})
+
+	It("should install the argocd-operator in its own namespace", func() {
+		serveAPIs(OLMGroup + "/v1alpha1")
+		provisioner, err := newGitOpsProvisioner(PatternsOperatorConfig{configKeyProvisioner: GitOpsProvisionerArgoCDOperator})
+		Expect(err).ToNot(HaveOccurred())
+		activeArgoNamespace = LegacyApplicationNamespace
+		provisioner.DetectArgoInstance(reconciler)
+		Expect(getClusterWideArgoNamespace()).To(Equal(ApplicationNamespace))
+
+		done, _, err := provisioner.Reconcile(reconciler, pattern)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(done).To(BeFalse())
+
+		_, err = reconciler.fullClient.CoreV1().Namespaces().Get(context.Background(), ArgoCDOperatorDefaultSubscriptionNamespace, metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		og, err := getOperatorGroup(reconciler.olmClient, ArgoCDOperatorDefaultSubscriptionNamespace)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(og).ToNot(BeNil())
+		sub, err := getSubscription(reconciler.olmClient, ArgoCDOperatorPackageName, ArgoCDOperatorDefaultSubscriptionNamespace)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sub.Spec.CatalogSource).To(Equal(ArgoCDOperatorDefaultCatalogSource))
+	})
+
+	Context("external", func() {
+		var provisioner GitOpsProvisioner
+
+		BeforeEach(func() {
+			var err error
+			provisioner, err = newGitOpsProvisioner(PatternsOperatorConfig{
+				configKeyProvisioner:           GitOpsProvisionerExternal,
+				configKeyExternalArgoNamespace: "platform-argocd",
+			})
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should adopt the configured instance without managing it", func() {
+			provisioner.DetectArgoInstance(reconciler)
+			Expect(getClusterWideArgoNamespace()).To(Equal("platform-argocd"))
+			Expect(getClusterWideArgoName()).To(Equal(ExternalArgoDefaultName))
+			Expect(provisioner.ManagesArgoCD()).To(BeFalse())
+		})
+
+		It("should wait for the application API and the namespace", func() {
+			done, _, err := provisioner.Reconcile(reconciler, pattern)
+			Expect(done).To(BeTrue())
+			Expect(err).ToNot(HaveOccurred())
+			expectCondition(pattern, api.GitOpsOperatorReady, metav1.ConditionFalse, api.ReasonReconcileFailed)
+
+			serveAPIs(ArgoCDGroup + "/v1alpha1")
+			done, _, _ = provisioner.Reconcile(reconciler, pattern)
+			Expect(done).To(BeTrue())
+			_, c := getPatternConditionByType(pattern.Status.Conditions, api.GitOpsOperatorReady)
+			Expect(c.Message).To(ContainSubstring("namespace platform-argocd"))
+
+			reconciler.fullClient = kubeclient.NewSimpleClientset()
+			serveAPIs(ArgoCDGroup + "/v1alpha1")
+			Expect(reconciler.Client.Create(context.Background(), &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "platform-argocd"}})).To(Succeed())
+			done, _, err = provisioner.Reconcile(reconciler, pattern)
+			Expect(done).To(BeFalse())
+			Expect(err).ToNot(HaveOccurred())
+		})
+	})
+})
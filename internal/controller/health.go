@@ -0,0 +1,84 @@
+package controllers
+
+import (
+	"fmt"
+
+	api "github.com/hybrid-cloud-patterns/patterns-operator/api/v1alpha1"
+)
+
+// applicationHealth classifies an application by the status that matters most to the pattern.
+// A degraded or missing application is reported as such even when it is also out of sync
+func applicationHealth(app *api.PatternApplicationInfo) api.PatternHealthStatus {
+	switch {
+	case app.AppHealthStatus == "Degraded":
+		return api.PatternDegraded
+	case app.AppHealthStatus == "Missing":
+		return api.PatternMissing
+	case app.AppSyncStatus == "OutOfSync":
+		return api.PatternOutOfSync
+	case app.AppHealthStatus == "Healthy" && app.AppSyncStatus == "Synced":
+		return api.PatternHealthy
+	}
+	// Progressing, Suspended, or not reported by Argo yet
+	return api.PatternProgressing
+}
+
+// aggregatePatternHealth rolls up the status of the applications of the pattern
+func aggregatePatternHealth(apps []api.PatternApplicationInfo) *api.PatternHealth {
+	h := &api.PatternHealth{Total: len(apps)}
+	for i := range apps {
+		switch applicationHealth(&apps[i]) {
+		case api.PatternDegraded:
+			h.Degraded++
+		case api.PatternMissing:
+			h.Missing++
+		case api.PatternOutOfSync:
+			h.OutOfSync++
+		case api.PatternHealthy:
+			h.Healthy++
+		default:
+			h.Progressing++
+		}
+	}
+
+	switch {
+	case h.Degraded > 0:
+		h.Status = api.PatternDegraded
+	case h.Missing > 0 || h.Total == 0:
+		h.Status = api.PatternMissing
+	case h.OutOfSync > 0:
+		h.Status = api.PatternOutOfSync
+	case h.Progressing > 0:
+		h.Status = api.PatternProgressing
+	default:
+		h.Status = api.PatternHealthy
+	}
+	return h
+}
+
+// updatePatternHealth recomputes status.health from all the applications of the pattern and sets the
+// Synced, OutOfSync, Degraded and Progressing conditions accordingly. Returns true if the status changed
+func updatePatternHealth(p *api.Pattern, apps []api.PatternApplicationInfo) bool {
+	health := aggregatePatternHealth(apps)
+	changed := p.Status.Health == nil || *p.Status.Health != *health
+	p.Status.Health = health
+
+	reason := string(health.Status)
+	message := fmt.Sprintf("%d of %d applications are healthy, %d progressing, %d degraded, %d missing, %d out of sync",
+		health.Healthy, health.Total, health.Progressing, health.Degraded, health.Missing, health.OutOfSync)
+	// Degraded and missing applications are not counted as out of sync, look at the sync status directly
+	synced, outOfSync := 0, 0
+	for i := range apps {
+		switch apps[i].AppSyncStatus {
+		case "Synced":
+			synced++
+		case "OutOfSync":
+			outOfSync++
+		}
+	}
+	changed = setPatternCondition(p, api.Synced, conditionStatus(health.Total > 0 && synced == health.Total), reason, message) || changed
+	changed = setPatternCondition(p, api.OutOfSync, conditionStatus(outOfSync > 0), reason, message) || changed
+	changed = setPatternCondition(p, api.Degraded, conditionStatus(health.Degraded > 0), reason, message) || changed
+	changed = setPatternCondition(p, api.Progressing, conditionStatus(health.Progressing > 0), reason, message) || changed
+	return changed
+}
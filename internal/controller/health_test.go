@@ -0,0 +1,77 @@
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/hybrid-cloud-patterns/patterns-operator/api/v1alpha1"
+)
+
+var _ = Describe("pattern health", func() {
+	app := func(name, sync, health string) api.PatternApplicationInfo {
+		return api.PatternApplicationInfo{Name: name, AppSyncStatus: sync, AppHealthStatus: health}
+	}
+
+	DescribeTable("classifying an application",
+		func(sync, health string, expected api.PatternHealthStatus) {
+			info := app("a", sync, health)
+			Expect(applicationHealth(&info)).To(Equal(expected))
+		},
+		Entry("synced and healthy", "Synced", "Healthy", api.PatternHealthy),
+		Entry("out of sync", "OutOfSync", "Healthy", api.PatternOutOfSync),
+		Entry("degraded and out of sync", "OutOfSync", "Degraded", api.PatternDegraded),
+		Entry("missing", "OutOfSync", "Missing", api.PatternMissing),
+		Entry("progressing", "Synced", "Progressing", api.PatternProgressing),
+		Entry("not reported yet", "", "", api.PatternProgressing),
+	)
+
+	It("should be missing without applications", func() {
+		h := aggregatePatternHealth(nil)
+		Expect(h.Status).To(Equal(api.PatternMissing))
+		Expect(h.Total).To(BeZero())
+	})
+
+	It("should count the applications and report the worst state", func() {
+		h := aggregatePatternHealth([]api.PatternApplicationInfo{
+			app("a", "Synced", "Healthy"),
+			app("b", "Synced", "Healthy"),
+			app("c", "OutOfSync", "Healthy"),
+			app("d", "Synced", "Progressing"),
+		})
+		Expect(*h).To(Equal(api.PatternHealth{
+			Status: api.PatternOutOfSync, Total: 4, Healthy: 2, OutOfSync: 1, Progressing: 1,
+		}))
+
+		h = aggregatePatternHealth([]api.PatternApplicationInfo{
+			app("a", "OutOfSync", "Missing"),
+			app("b", "Synced", "Degraded"),
+		})
+		Expect(h.Status).To(Equal(api.PatternDegraded))
+		Expect(h.Missing).To(Equal(1))
+		Expect(h.Degraded).To(Equal(1))
+	})
+
+	It("should set the aggregated conditions", func() {
+		pattern := buildPatternManifest()
+		pattern.Status.Applications = []api.PatternApplicationInfo{
+			app("a", "Synced", "Healthy"),
+			app("b", "OutOfSync", "Degraded"),
+		}
+		Expect(updatePatternHealth(pattern, pattern.Status.Applications)).To(BeTrue())
+		Expect(pattern.Status.Health.Status).To(Equal(api.PatternDegraded))
+		expectCondition(pattern, api.Synced, metav1.ConditionFalse, "Degraded")
+		expectCondition(pattern, api.OutOfSync, metav1.ConditionTrue, "Degraded")
+		c := expectCondition(pattern, api.Degraded, metav1.ConditionTrue, "Degraded")
+		Expect(c.Message).To(Equal("1 of 2 applications are healthy, 0 progressing, 1 degraded, 0 missing, 0 out of sync"))
+		expectCondition(pattern, api.Progressing, metav1.ConditionFalse, "Degraded")
+		Expect(updatePatternHealth(pattern, pattern.Status.Applications)).To(BeFalse())
+
+		pattern.Status.Applications[1] = app("b", "Synced", "Healthy")
+		Expect(updatePatternHealth(pattern, pattern.Status.Applications)).To(BeTrue())
+		Expect(pattern.Status.Health.Status).To(Equal(api.PatternHealthy))
+		expectCondition(pattern, api.Synced, metav1.ConditionTrue, "Healthy")
+		expectCondition(pattern, api.OutOfSync, metav1.ConditionFalse, "Healthy")
+		expectCondition(pattern, api.Degraded, metav1.ConditionFalse, "Healthy")
+	})
+})
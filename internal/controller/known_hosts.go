@@ -0,0 +1,107 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"strings"
+
+	stdssh "golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// ConfigMap where Argo CD keeps the SSH known hosts of the repositories it syncs from
+	ArgoCDKnownHostsConfigMap = "argocd-ssh-known-hosts-cm"
+	// Key of the known hosts in the Argo CD ConfigMap and in the one of the operator config
+	knownHostsConfigMapKey = "ssh_known_hosts"
+)
+
+// sshHostKeyError is returned when the git server presents a host key that is not in the known hosts
+type sshHostKeyError struct {
+	host string
+	err  *knownhosts.KeyError
+}
+
+func (e *sshHostKeyError) Error() string {
+	if len(e.err.Want) == 0 {
+		return fmt.Sprintf("SSH host key of %s is not in the known hosts", e.host)
+	}
+	return fmt.Sprintf("SSH host key of %s does not match the known hosts, the connection may have been intercepted", e.host)
+}
+
+func (e *sshHostKeyError) Unwrap() error {
+	return e.err
+}
+
+func isSSHHostKeyError(err error) bool {
+	var hostKeyErr *sshHostKeyError
+	return errors.As(err, &hostKeyErr)
+}
+
+// withSSHKnownHosts returns a copy of the git auth secret whose sshKnownHosts also holds the known hosts of the
+// ConfigMap named in the operator config and of Argo CD. Missing ConfigMaps are skipped, the lines of the secret come first
+func (r *PatternReconciler) withSSHKnownHosts(secret map[string][]byte, patternsOperatorConfig PatternsOperatorConfig) map[string][]byte {
+	sources := []types.NamespacedName{}
+	if ref := patternsOperatorConfig.getStringValue(configKeySSHKnownHostsConfigMap); ref != "" {
+		namespace, name, found := strings.Cut(ref, "/")
+		if !found {
+			namespace, name = DetectOperatorNamespace(), ref
+		}
+		sources = append(sources, types.NamespacedName{Namespace: namespace, Name: name})
+	}
+	sources = append(sources, types.NamespacedName{Namespace: getClusterWideArgoNamespace(), Name: ArgoCDKnownHostsConfigMap})
+
+	knownHosts := [][]byte{getField(secret, secretFieldSSHKnownHosts)}
+	for _, source := range sources {
+		cm := &corev1.ConfigMap{}
+		if err := r.Get(context.Background(), source, cm); err != nil {
+			log.Printf("Skipping SSH known hosts from ConfigMap %s: %v", source, err)
+			continue
+		}
+		knownHosts = append(knownHosts, []byte(cm.Data[knownHostsConfigMapKey]))
+	}
+
+	out := make(map[string][]byte, len(secret)+1)
+	for k, v := range secret {
+		out[k] = v
+	}
+	out[secretFieldSSHKnownHosts] = bytes.Join(knownHosts, []byte("\n"))
+	return out
+}
+
+// newKnownHostsCallback verifies host keys against known_hosts content. The knownhosts package only reads files,
+// the content goes through a temporary one
+func newKnownHostsCallback(knownHosts []byte) (stdssh.HostKeyCallback, error) {
+	f, err := os.CreateTemp("", "vp-known-hosts-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(f.Name())
+	_, err = f.Write(knownHosts)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	callback, err := knownhosts.New(f.Name())
+	if err != nil {
+		return nil, fmt.Errorf("could not parse the SSH known hosts: %w", err)
+	}
+	return func(hostname string, remote net.Addr, key stdssh.PublicKey) error {
+		err := callback(hostname, remote, key)
+		var keyErr *knownhosts.KeyError
+		if errors.As(err, &keyErr) {
+			return &sshHostKeyError{host: hostname, err: keyErr}
+		}
+		return err
+	}, nil
+}
@@ -0,0 +1,69 @@
+package controllers
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+	"net"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	stdssh "golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("SSH known hosts", func() {
+	newHostKey := func() stdssh.PublicKey {
+		pub, _, err := ed25519.GenerateKey(rand.Reader)
+		Expect(err).ToNot(HaveOccurred())
+		key, err := stdssh.NewPublicKey(pub)
+		Expect(err).ToNot(HaveOccurred())
+		return key
+	}
+	remote := &net.TCPAddr{IP: net.ParseIP("192.0.2.10"), Port: 22}
+
+	It("should accept known host keys and report unknown and mismatching ones", func() {
+		hostKey := newHostKey()
+		callback, err := newKnownHostsCallback([]byte(knownhosts.Line([]string{"git.example.com"}, hostKey)))
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(callback("git.example.com:22", remote, hostKey)).To(Succeed())
+
+		err = callback("git.example.com:22", remote, newHostKey())
+		Expect(isSSHHostKeyError(fmt.Errorf("ssh: handshake failed: %w", err))).To(BeTrue())
+		Expect(err).To(MatchError(ContainSubstring("does not match the known hosts")))
+
+		err = callback("other.example.com:22", remote, hostKey)
+		Expect(isSSHHostKeyError(err)).To(BeTrue())
+		Expect(err).To(MatchError(ContainSubstring("is not in the known hosts")))
+	})
+
+	It("should reject malformed known hosts", func() {
+		_, err := newKnownHostsCallback([]byte("git.example.com ssh-ed25519 not-base64"))
+		Expect(err).To(MatchError(ContainSubstring("could not parse the SSH known hosts")))
+	})
+
+	It("should merge the token secret, the configured ConfigMap and the one of Argo CD", func() {
+		reconciler := newFakeReconciler(
+			&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "known-hosts", Namespace: suggestedOperatorNamespace},
+				Data:       map[string]string{knownHostsConfigMapKey: "from-operator-config"},
+			},
+			&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: ArgoCDKnownHostsConfigMap, Namespace: getClusterWideArgoNamespace()},
+				Data:       map[string]string{knownHostsConfigMapKey: "from-argo"},
+			},
+		)
+		secret := map[string][]byte{"sshPrivateKey": []byte("key"), secretFieldSSHKnownHosts: []byte("from-secret")}
+
+		merged := reconciler.withSSHKnownHosts(secret, PatternsOperatorConfig{configKeySSHKnownHostsConfigMap: "known-hosts"})
+		Expect(string(merged[secretFieldSSHKnownHosts])).To(Equal("from-secret\nfrom-operator-config\nfrom-argo"))
+		Expect(merged["sshPrivateKey"]).To(Equal([]byte("key")))
+		Expect(string(secret[secretFieldSSHKnownHosts])).To(Equal("from-secret"))
+
+		merged = reconciler.withSSHKnownHosts(secret, PatternsOperatorConfig{configKeySSHKnownHostsConfigMap: "other/missing"})
+		Expect(string(merged[secretFieldSSHKnownHosts])).To(Equal("from-secret\nfrom-argo"))
+	})
+})
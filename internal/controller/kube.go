@@ -19,6 +19,7 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"log"
 
 	routeclient "github.com/openshift/client-go/route/clientset/versioned"
 	v1 "k8s.io/api/core/v1"
@@ -31,6 +32,8 @@ import (
 	kubeclient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	"gopkg.in/yaml.v3"
+
+	api "github.com/hybrid-cloud-patterns/patterns-operator/api/v1alpha1"
 )
 
 func haveNamespace(controllerClient kubeclient.Client, name string) bool {
@@ -87,10 +90,23 @@ func referSameObject(a, b *metav1.OwnerReference) bool {
 }
 
 func checkAPIVersion(clientset kubernetes.Interface, group, version string) error {
+	found, err := haveAPIVersion(clientset, group, version)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("API version %s/%s not available", group, version)
+	}
+	return nil
+}
+
+// haveAPIVersion reports whether the cluster serves the given API group and version. Unlike
+// checkAPIVersion it tells a failed discovery apart from an API that is not installed
+func haveAPIVersion(clientset kubernetes.Interface, group, version string) (bool, error) {
 	// Get the list of API groups available in the cluster
 	apiGroups, err := clientset.Discovery().ServerGroups()
 	if err != nil {
-		return fmt.Errorf("failed to get API groups: %v", err)
+		return false, fmt.Errorf("failed to get API groups: %v", err)
 	}
 
 	// Iterate through the API groups to find the specified group and version
@@ -99,13 +115,13 @@ func checkAPIVersion(clientset kubernetes.Interface, group, version string) erro
 		if apiGroup.Name == group {
 			for _, apiVersion := range apiGroup.Versions {
 				if apiVersion.Version == version {
-					return nil
+					return true, nil
 				}
 			}
 		}
 	}
 
-	return fmt.Errorf("API version %s/%s not available", group, version)
+	return false, nil
 }
 
 func getRoute(routeClient routeclient.Interface, routeName, namespace string) (string, error) {
@@ -135,3 +151,104 @@ func getSecret(fullClient kubernetes.Interface, name, ns string) (*v1.Secret, er
 	}
 	return secret, nil
 }
+
+// resolveExtraParameters reads the value of the extra parameters that reference a ConfigMap key in the
+// namespace of the pattern, by parameter name. Parameters whose optional reference is missing are left out.
+// The values are kept apart from the pattern so that they are never written back to it.
+// Parameters read from a Secret are left out too: the Argo Application would hold their value in plain text
+func resolveExtraParameters(fullClient kubernetes.Interface, p *api.Pattern) (map[string]string, error) {
+	parameterValues := map[string]string{}
+	for _, extra := range p.Spec.ExtraParameters {
+		if extra.ValueFrom == nil {
+			continue
+		}
+		if extra.ValueFrom.SecretKeyRef != nil && extra.ValueFrom.ConfigMapKeyRef == nil {
+			logOnce(fmt.Sprintf("Extra parameter %q of pattern %s/%s reads a Secret, skipping it", extra.Name, p.Namespace, p.Name))
+			continue
+		}
+		value, found, err := getParameterSourceValue(fullClient, p.Namespace, extra.ValueFrom)
+		if err != nil {
+			return nil, fmt.Errorf("resolving extra parameter %q: %w", extra.Name, err)
+		}
+		if !found {
+			log.Printf("Optional source of extra parameter %q not found, skipping it", extra.Name)
+			continue
+		}
+		parameterValues[extra.Name] = value
+	}
+	return parameterValues, nil
+}
+
+// getParameterSourceValue returns the value of the ConfigMap or Secret key selected by source.
+// found is false when an optional ConfigMap, Secret or key does not exist
+func getParameterSourceValue(fullClient kubernetes.Interface, namespace string, source *api.PatternParameterSource) (value string, found bool, err error) {
+	if (source.ConfigMapKeyRef == nil) == (source.SecretKeyRef == nil) {
+		return "", false, fmt.Errorf("exactly one of configMapKeyRef or secretKeyRef must be set")
+	}
+
+	if ref := source.ConfigMapKeyRef; ref != nil {
+		optional := ref.Optional != nil && *ref.Optional
+		cm, err := fullClient.CoreV1().ConfigMaps(namespace).Get(context.Background(), ref.Name, metav1.GetOptions{})
+		if err != nil {
+			if errors.IsNotFound(err) && optional {
+				return "", false, nil
+			}
+			return "", false, err
+		}
+		if v, ok := cm.Data[ref.Key]; ok {
+			return v, true, nil
+		}
+		if v, ok := cm.BinaryData[ref.Key]; ok {
+			return string(v), true, nil
+		}
+		if optional {
+			return "", false, nil
+		}
+		return "", false, fmt.Errorf("key %s not found in ConfigMap %s/%s", ref.Key, namespace, ref.Name)
+	}
+
+	ref := source.SecretKeyRef
+	optional := ref.Optional != nil && *ref.Optional
+	secret, err := getSecret(fullClient, ref.Name, namespace)
+	if err != nil {
+		if errors.IsNotFound(err) && optional {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+	if v, ok := secret.Data[ref.Key]; ok {
+		return string(v), true, nil
+	}
+	if optional {
+		return "", false, nil
+	}
+	return "", false, fmt.Errorf("key %s not found in Secret %s/%s", ref.Key, namespace, ref.Name)
+}
+
+// parameterSourceReferences returns true if one of the extra parameters of the pattern, or the
+// CA bundle of its git server, reads its value from the ConfigMap or Secret obj. The watches only
+// cache the metadata of the objects, isSecret tells which kind obj is
+func parameterSourceReferences(p *api.Pattern, obj kubeclient.Object, isSecret bool) bool {
+	if obj.GetNamespace() != p.Namespace {
+		return false
+	}
+	sources := []*api.PatternParameterSource{p.Spec.GitConfig.CABundleFrom, p.Spec.GitConfig.SigningKeysFrom}
+	// The extra parameters read from a Secret are skipped, see resolveExtraParameters
+	if !isSecret {
+		for _, extra := range p.Spec.ExtraParameters {
+			sources = append(sources, extra.ValueFrom)
+		}
+	}
+	for _, source := range sources {
+		if source == nil {
+			continue
+		}
+		if isSecret && source.SecretKeyRef != nil && source.SecretKeyRef.Name == obj.GetName() {
+			return true
+		}
+		if !isSecret && source.ConfigMapKeyRef != nil && source.ConfigMapKeyRef.Name == obj.GetName() {
+			return true
+		}
+	}
+	return false
+}
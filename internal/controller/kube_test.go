@@ -22,6 +22,8 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+
+	api "github.com/hybrid-cloud-patterns/patterns-operator/api/v1alpha1"
 )
 
 var _ = Describe("HaveNamespace", func() {
@@ -627,6 +629,104 @@ var _ = Describe("GetSecret", func() {
 	})
 })
 
+var _ = Describe("ResolveExtraParameters", func() {
+	var (
+		clientset *kubefake.Clientset
+		pattern   *api.Pattern
+		optional  bool
+	)
+
+	BeforeEach(func() {
+		optional = true
+		clientset = kubefake.NewSimpleClientset(
+			&v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: "default"},
+				Data:       map[string]string{"domain": "example.com"},
+			},
+			&v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "credentials", Namespace: "default"},
+				Data:       map[string][]byte{"token": []byte("s3cr3t")},
+			},
+		)
+		pattern = &api.Pattern{
+			ObjectMeta: metav1.ObjectMeta{Name: "pattern", Namespace: "default"},
+			Spec: api.PatternSpec{
+				ExtraParameters: []api.PatternParameter{
+					{Name: "global.plain", Value: "value"},
+					{Name: "global.domain", ValueFrom: &api.PatternParameterSource{
+						ConfigMapKeyRef: &v1.ConfigMapKeySelector{LocalObjectReference: v1.LocalObjectReference{Name: "settings"}, Key: "domain"},
+					}},
+					{Name: "global.token", ValueFrom: &api.PatternParameterSource{
+						SecretKeyRef: &v1.SecretKeySelector{LocalObjectReference: v1.LocalObjectReference{Name: "credentials"}, Key: "token"},
+					}},
+				},
+			},
+		}
+	})
+
+	It("should resolve the values from ConfigMap keys and skip the Secret keys", func() {
+		parameterValues, err := resolveExtraParameters(clientset, pattern)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(parameterValues).To(Equal(map[string]string{"global.domain": "example.com"}))
+		Expect(pattern.Spec.ExtraParameters).To(HaveLen(3))
+		Expect(pattern.Spec.ExtraParameters[2].Value).To(BeEmpty())
+		Expect(getExtraParameters(pattern, parameterValues)).ToNot(ContainElement(HaveField("Name", "global.token")))
+	})
+
+	It("should fail when a referenced key is missing", func() {
+		pattern.Spec.ExtraParameters[1].ValueFrom.ConfigMapKeyRef.Key = "missing"
+		_, err := resolveExtraParameters(clientset, pattern)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("global.domain"))
+	})
+
+	It("should fail when a referenced object is missing", func() {
+		pattern.Spec.ExtraParameters[1].ValueFrom.ConfigMapKeyRef.Name = "missing"
+		_, err := resolveExtraParameters(clientset, pattern)
+		Expect(err).To(HaveOccurred())
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should leave out parameters whose optional source is missing without changing the pattern", func() {
+		pattern.Spec.ExtraParameters[1].ValueFrom.ConfigMapKeyRef.Name = "missing"
+		pattern.Spec.ExtraParameters[1].ValueFrom.ConfigMapKeyRef.Optional = &optional
+		pattern.Spec.ExtraParameters[2].ValueFrom.SecretKeyRef.Key = "missing"
+		pattern.Spec.ExtraParameters[2].ValueFrom.SecretKeyRef.Optional = &optional
+		spec := pattern.Spec.DeepCopy()
+		parameterValues, err := resolveExtraParameters(clientset, pattern)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(parameterValues).To(BeEmpty())
+		Expect(pattern.Spec).To(Equal(*spec))
+		Expect(getExtraParameters(pattern, parameterValues)).To(ConsistOf(HaveField("Name", "global.plain")))
+	})
+
+	It("should reject a source with both or none of the references set", func() {
+		pattern.Spec.ExtraParameters[1].ValueFrom.SecretKeyRef = pattern.Spec.ExtraParameters[2].ValueFrom.SecretKeyRef
+		_, err := resolveExtraParameters(clientset, pattern)
+		Expect(err).To(HaveOccurred())
+		pattern.Spec.ExtraParameters[1].ValueFrom = &api.PatternParameterSource{}
+		_, err = resolveExtraParameters(clientset, pattern)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should match the ConfigMaps referenced by the extra parameters", func() {
+		Expect(parameterSourceReferences(pattern, &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: "default"}}, false)).To(BeTrue())
+		// The Secret parameters are skipped, so their Secret is not watched
+		Expect(parameterSourceReferences(pattern, &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "credentials", Namespace: "default"}}, true)).To(BeFalse())
+		Expect(parameterSourceReferences(pattern, &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: "default"}}, true)).To(BeFalse())
+		Expect(parameterSourceReferences(pattern, &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: "other"}}, false)).To(BeFalse())
+	})
+
+	It("should match the ConfigMap holding the CA bundle of the git server", func() {
+		caBundle := &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "git-ca", Namespace: "default"}}
+		Expect(parameterSourceReferences(pattern, caBundle, false)).To(BeFalse())
+		pattern.Spec.GitConfig.CABundleFrom = &api.PatternParameterSource{
+			ConfigMapKeyRef: &v1.ConfigMapKeySelector{LocalObjectReference: v1.LocalObjectReference{Name: "git-ca"}, Key: "ca.crt"},
+		}
+		Expect(parameterSourceReferences(pattern, caBundle, false)).To(BeTrue())
+	})
+})
+
 // CustomClientset is a wrapper around fake.Clientset that overrides the Discovery method
 type CustomClientset struct {
 	*kubefake.Clientset
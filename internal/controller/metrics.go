@@ -0,0 +1,133 @@
+package controllers
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	api "github.com/hybrid-cloud-patterns/patterns-operator/api/v1alpha1"
+)
+
+const metricsNamespace = "patterns_operator"
+
+// Reconcile steps reported in the step duration metric
+const (
+	reconcileStepSubscription = "subscription"
+	reconcileStepArgoInfra    = "argo_infra"
+	reconcileStepGitea        = "gitea"
+	reconcileStepGitCheckout  = "git_checkout"
+	reconcileStepApplication  = "application"
+)
+
+// Outcomes of a reconcile step. A step that changed something requeues the pattern
+// instead of moving on to the next one
+const (
+	reconcileOutcomeCompleted = "completed"
+	reconcileOutcomeRequeued  = "requeued"
+	reconcileOutcomeFailed    = "failed"
+)
+
+var (
+	reconcileStepDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "reconcile_step_duration_seconds",
+		Help:      "Duration of the pattern reconcile steps by outcome.",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120},
+	}, []string{"step", "outcome"})
+
+	gitOperationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "git_operation_duration_seconds",
+		Help:      "Duration of the git clone and fetch operations.",
+		Buckets:   []float64{0.1, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300},
+	}, []string{"operation"})
+
+	gitOperationFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "git_operation_failures_total",
+		Help:      "Number of failed git clone and fetch operations.",
+	}, []string{"operation"})
+
+	patternApplications = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "applications",
+		Help:      "Number of Argo CD applications of a pattern by sync and health status.",
+	}, []string{"pattern", "namespace", "sync_status", "health_status"})
+
+	patternDeletionPhase = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "deletion_phase",
+		Help:      "Current deletion phase of a pattern, set to 1 for the active phase.",
+	}, []string{"pattern", "namespace", "phase"})
+
+	analyticsFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "analytics_failures_total",
+		Help:      "Number of analytics events that could not be sent.",
+	}, []string{"event"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		reconcileStepDuration,
+		gitOperationDuration,
+		gitOperationFailures,
+		patternApplications,
+		patternDeletionPhase,
+		analyticsFailures,
+	)
+}
+
+// observeReconcileStep records how long a reconcile step took. done and the last error of the
+// pattern tell whether the step completed, made a change and requeued, or failed
+func observeReconcileStep(step string, start time.Time, done bool, p *api.Pattern) {
+	outcome := reconcileOutcomeCompleted
+	if done {
+		outcome = reconcileOutcomeRequeued
+		if p.Status.LastError != "" {
+			outcome = reconcileOutcomeFailed
+		}
+	}
+	reconcileStepDuration.WithLabelValues(step, outcome).Observe(time.Since(start).Seconds())
+}
+
+// observeReconcileStepError records how long a reconcile step that reports its failure as an error took
+func observeReconcileStepError(step string, start time.Time, err error) {
+	outcome := reconcileOutcomeCompleted
+	if err != nil {
+		outcome = reconcileOutcomeFailed
+	}
+	reconcileStepDuration.WithLabelValues(step, outcome).Observe(time.Since(start).Seconds())
+}
+
+// observeGitOperation records the duration of a git clone or fetch and counts its failures
+func observeGitOperation(operation string, start time.Time, err error) {
+	gitOperationDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+	if err != nil {
+		gitOperationFailures.WithLabelValues(operation).Inc()
+	}
+}
+
+// setApplicationMetrics replaces the application counts of the pattern
+func setApplicationMetrics(p *api.Pattern, apps []api.PatternApplicationInfo) {
+	patternApplications.DeletePartialMatch(prometheus.Labels{"pattern": p.Name, "namespace": p.Namespace})
+	for i := range apps {
+		patternApplications.WithLabelValues(p.Name, p.Namespace, apps[i].AppSyncStatus, apps[i].AppHealthStatus).Inc()
+	}
+}
+
+// setDeletionPhaseMetric marks the current deletion phase of the pattern
+func setDeletionPhaseMetric(p *api.Pattern) {
+	patternDeletionPhase.DeletePartialMatch(prometheus.Labels{"pattern": p.Name, "namespace": p.Namespace})
+	if p.Status.DeletionPhase != "" {
+		patternDeletionPhase.WithLabelValues(p.Name, p.Namespace, string(p.Status.DeletionPhase)).Set(1)
+	}
+}
+
+// deletePatternMetrics drops the series of a pattern once it is gone
+func deletePatternMetrics(p *api.Pattern) {
+	labels := prometheus.Labels{"pattern": p.Name, "namespace": p.Namespace}
+	patternApplications.DeletePartialMatch(labels)
+	patternDeletionPhase.DeletePartialMatch(labels)
+}
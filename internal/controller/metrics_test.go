@@ -0,0 +1,85 @@
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+
+	api "github.com/hybrid-cloud-patterns/patterns-operator/api/v1alpha1"
+)
+
+func histogramSampleCount(o prometheus.Observer) uint64 {
+	m := &dto.Metric{}
+	Expect(o.(prometheus.Metric).Write(m)).To(Succeed())
+	return m.GetHistogram().GetSampleCount()
+}
+
+var _ = Describe("Metrics", func() {
+	var pattern *api.Pattern
+
+	BeforeEach(func() {
+		pattern = buildPatternManifest()
+		pattern.Name = "metrics-pattern"
+		// Other specs reconcile patterns too, only count the series of this one
+		patternApplications.Reset()
+		patternDeletionPhase.Reset()
+	})
+
+	AfterEach(func() {
+		deletePatternMetrics(pattern)
+	})
+
+	It("should report the outcome of reconcile steps", func() {
+		completed := histogramSampleCount(reconcileStepDuration.WithLabelValues(reconcileStepArgoInfra, reconcileOutcomeCompleted))
+		requeued := histogramSampleCount(reconcileStepDuration.WithLabelValues(reconcileStepArgoInfra, reconcileOutcomeRequeued))
+		failed := histogramSampleCount(reconcileStepDuration.WithLabelValues(reconcileStepArgoInfra, reconcileOutcomeFailed))
+
+		observeReconcileStep(reconcileStepArgoInfra, time.Now(), false, pattern)
+		observeReconcileStep(reconcileStepArgoInfra, time.Now(), true, pattern)
+		pattern.Status.LastError = "waiting for creation"
+		observeReconcileStep(reconcileStepArgoInfra, time.Now(), true, pattern)
+
+		Expect(histogramSampleCount(reconcileStepDuration.WithLabelValues(reconcileStepArgoInfra, reconcileOutcomeCompleted))).To(Equal(completed + 1))
+		Expect(histogramSampleCount(reconcileStepDuration.WithLabelValues(reconcileStepArgoInfra, reconcileOutcomeRequeued))).To(Equal(requeued + 1))
+		Expect(histogramSampleCount(reconcileStepDuration.WithLabelValues(reconcileStepArgoInfra, reconcileOutcomeFailed))).To(Equal(failed + 1))
+	})
+
+	It("should count failed git operations", func() {
+		failures := testutil.ToFloat64(gitOperationFailures.WithLabelValues("fetch"))
+		observeGitOperation("fetch", time.Now(), nil)
+		observeGitOperation("fetch", time.Now(), fmt.Errorf("authentication required"))
+		Expect(testutil.ToFloat64(gitOperationFailures.WithLabelValues("fetch"))).To(Equal(failures + 1))
+	})
+
+	It("should count the applications by sync and health status", func() {
+		pattern.Status.Applications = buildTestApplicationInfoArray()
+		pattern.Status.Applications = append(pattern.Status.Applications, api.PatternApplicationInfo{
+			Name: "bar", AppSyncStatus: "Synced", AppHealthStatus: "Healthy",
+		})
+		setApplicationMetrics(pattern, pattern.Status.Applications)
+		Expect(testutil.ToFloat64(patternApplications.WithLabelValues(pattern.Name, pattern.Namespace, "Synced", "Healthy"))).To(Equal(2.0))
+		Expect(testutil.ToFloat64(patternApplications.WithLabelValues(pattern.Name, pattern.Namespace, "Degraded", "Synced"))).To(Equal(1.0))
+
+		// Statuses that are gone are dropped
+		pattern.Status.Applications = pattern.Status.Applications[:1]
+		setApplicationMetrics(pattern, pattern.Status.Applications)
+		Expect(testutil.CollectAndCount(patternApplications)).To(Equal(1))
+	})
+
+	It("should only report the current deletion phase", func() {
+		pattern.Status.DeletionPhase = api.DeleteSpokeChildApps
+		setDeletionPhaseMetric(pattern)
+		pattern.Status.DeletionPhase = api.DeleteSpoke
+		setDeletionPhaseMetric(pattern)
+		Expect(testutil.CollectAndCount(patternDeletionPhase)).To(Equal(1))
+		Expect(testutil.ToFloat64(patternDeletionPhase.WithLabelValues(pattern.Name, pattern.Namespace, string(api.DeleteSpoke)))).To(Equal(1.0))
+
+		deletePatternMetrics(pattern)
+		Expect(testutil.CollectAndCount(patternDeletionPhase)).To(BeZero())
+	})
+})
@@ -21,6 +21,7 @@ import (
 	"context"
 	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -28,13 +29,16 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/go-logr/logr"
 	"github.com/hybrid-cloud-patterns/patterns-operator/internal/controller/console"
 
 	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -43,11 +47,13 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	crcontroller "sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	klog "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
@@ -56,20 +62,53 @@ import (
 
 	argoapi "github.com/argoproj/argo-cd/v3/pkg/apis/application/v1alpha1"
 	argoclient "github.com/argoproj/argo-cd/v3/pkg/client/clientset/versioned"
+	configv1 "github.com/openshift/api/config/v1"
 	configclient "github.com/openshift/client-go/config/clientset/versioned"
 	routeclient "github.com/openshift/client-go/route/clientset/versioned"
 	v1 "github.com/operator-framework/api/pkg/operators/v1"
+	olmapi "github.com/operator-framework/api/pkg/operators/v1alpha1"
 
 	olmclient "github.com/operator-framework/operator-lifecycle-manager/pkg/api/client/clientset/versioned"
 	"k8s.io/client-go/kubernetes"
-
-	//	olmapi "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	"k8s.io/client-go/tools/record"
 
 	api "github.com/hybrid-cloud-patterns/patterns-operator/api/v1alpha1"
 	operatorclient "github.com/openshift/client-go/operator/clientset/versioned/typed/operator/v1"
 )
 
-const ReconcileLoopRequeueTime = 180 * time.Second
+const (
+	// Interval between two reconciliations of a pattern that does not set spec.reconcileMinutes
+	ReconcileLoopRequeueTime = 180 * time.Second
+	// Delay before retrying a failed step, doubled on every consecutive failure
+	ReconcileErrorRequeueTime = 1 * time.Minute
+	// Upper bound of the delay between retries of a failing step
+	ReconcileErrorMaxRequeueTime = 30 * time.Minute
+	// Delay before continuing the loop after a step that made progress, status updates do not trigger a reconcile
+	ReconcileStepRequeueTime = 1 * time.Second
+	// Delay between the polls of a pattern being deleted
+	DeletionRequeueTime = 2 * time.Minute
+	// Changes to the applications of a pattern within this window are handled by a single reconcile
+	ApplicationEventDebounce = 10 * time.Second
+)
+
+// GitAuthSecretCopyName prefixes the name of the copies of spec.gitSpec.tokenSecret in the Argo namespaces.
+// Older versions of the operator named the copy after the prefix only
+const GitAuthSecretCopyName = "vp-private-repo-credentials"
+
+// Fields of the git auth secret besides the credentials
+const (
+	// known_hosts lines of the git server, checked in addition to the configured known hosts ConfigMaps
+	secretFieldSSHKnownHosts = "sshKnownHosts"
+	// Same as in the Argo CD repository secrets, "true" skips the host key verification of the git server
+	secretFieldInsecure = "insecure"
+)
+
+// Credential fields of the git auth secret besides username and password, named as in the Argo CD repository secrets
+const (
+	secretFieldBearerToken       = "bearerToken"
+	secretFieldTLSClientCertData = "tlsClientCertData"
+	secretFieldTLSClientCertKey  = "tlsClientCertKey"
+)
 
 const (
 	secretFieldUsername   = "username"
@@ -86,6 +125,7 @@ type PatternReconciler struct {
 	client.Client
 	Scheme          *runtime.Scheme
 	AnalyticsClient VpAnalyticsInterface
+	Recorder        record.EventRecorder
 
 	logger logr.Logger
 
@@ -99,10 +139,12 @@ type PatternReconciler struct {
 	operatorClient  operatorclient.OperatorV1Interface
 	gitOperations   GitOperations
 	giteaOperations GiteaOperations
+	platform        ClusterPlatform
 
-	mgr                ctrl.Manager
-	ctrl               crcontroller.Controller
-	argoCDWatchStarted bool
+	mgr                     ctrl.Manager
+	ctrl                    crcontroller.Controller
+	argoCDWatchStarted      bool
+	applicationWatchStarted bool
 }
 
 //+kubebuilder:rbac:groups=gitops.hybrid-cloud-patterns.io,resources=patterns,verbs=get;list;watch;create;update;patch;delete
@@ -111,21 +153,23 @@ type PatternReconciler struct {
 //+kubebuilder:rbac:groups=config.openshift.io,resources=clusterversions,verbs=list;get
 //+kubebuilder:rbac:groups=config.openshift.io,resources=ingresses,verbs=list;get
 //+kubebuilder:rbac:groups=config.openshift.io,resources=infrastructures,verbs=list;get
+//+kubebuilder:rbac:groups=config.openshift.io,resources=proxies,verbs=list;get;watch
 //+kubebuilder:rbac:groups="",resources=nodes,verbs=get;list
 //+kubebuilder:rbac:groups=machine.openshift.io,resources=machines,verbs=get;list
 //+kubebuilder:rbac:groups="",resources=namespaces,verbs=list;watch;delete;update;get;create;patch
 //+kubebuilder:rbac:groups=console.openshift.io,resources=consolelinks,verbs=get;list;create;update;patch;delete
 //+kubebuilder:rbac:groups=argoproj.io,resources=argocds,verbs=list;watch;get;create;update;patch;delete
-//+kubebuilder:rbac:groups=argoproj.io,resources=applications,verbs=list;get;create;update;patch;delete
+//+kubebuilder:rbac:groups=argoproj.io,resources=applications,verbs=list;watch;get;create;update;patch;delete
 //+kubebuilder:rbac:groups=operators.coreos.com,resources=subscriptions,verbs=list;get;create;update;patch;delete
 //+kubebuilder:rbac:groups=operators.coreos.com,resources=operatorgroups,verbs=list;get;create;update;patch;delete
 //+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups="operator.open-cluster-management.io",resources=multiclusterhubs,verbs=get;list
 //+kubebuilder:rbac:groups=operator.openshift.io,resources="openshiftcontrollermanagers",resources=openshiftcontrollermanagers,verbs=get;list
-//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;create;update;watch
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;create;update;delete;watch
 //+kubebuilder:rbac:groups="view.open-cluster-management.io",resources=managedclusterviews,verbs=create
 //+kubebuilder:rbac:groups="cluster.open-cluster-management.io",resources=managedclusters,verbs=list;delete
 //+kubebuilder:rbac:groups="route.openshift.io",resources=routes,verbs=list;get
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -162,6 +206,7 @@ func (r *PatternReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		r.logger.Info("Error reading the request object, requeuing.")
 		return reconcile.Result{}, err
 	}
+	dropLegacyConditions(instance)
 
 	var patternsOperatorConfig PatternsOperatorConfig
 
@@ -170,6 +215,13 @@ func (r *PatternReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	if err != nil {
 		return r.actionPerformed(instance, "failed to get the configuration ConfigMap", err)
 	}
+
+	// Leave the cluster alone while suspended, only the application status is kept up to date.
+	// A suspended pattern being deleted is still cleaned up
+	if isPatternSuspended(instance) && instance.DeletionTimestamp.IsZero() {
+		return r.reconcileSuspended(instance)
+	}
+
 	if operatorConfigMap == nil { // If the ConfigMap doesn't exist, we create it
 		operatorConfigMap, err = CreatePatternsOperatorConfigMap(ctx, r.Client)
 		if err != nil {
@@ -179,8 +231,20 @@ func (r *PatternReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		patternsOperatorConfig = operatorConfigMap.Data
 	}
 
-	if err := console.CreateOrUpdateCatalog(ctx, r.Client, operatorConfigMap); err != nil {
-		return r.actionPerformed(instance, "unable to create/update catalog deployment", err)
+	platform, err := r.clusterPlatform()
+	if err != nil {
+		return r.actionPerformed(instance, "detecting the cluster platform", err)
+	}
+	// Git, Gitea, GitHub App and analytics traffic goes through the cluster proxy
+	if err := r.reconcileClusterProxy(ctx); err != nil {
+		return r.actionPerformed(instance, "reading the cluster proxy", err)
+	}
+
+	// The catalog and the console plugin are served through the OpenShift console
+	if platform.IsOpenShift() {
+		if err := console.CreateOrUpdateCatalog(ctx, r.Client, operatorConfigMap); err != nil {
+			return r.actionPerformed(instance, "unable to create/update catalog deployment", err)
+		}
 	}
 
 	// Remove the ArgoCD application on deletion
@@ -191,7 +255,7 @@ func (r *PatternReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 			err = r.Update(context.TODO(), instance)
 			return r.actionPerformed(instance, "updated finalizer", err)
 		}
-	} else if err = r.finalizeObject(instance); err != nil {
+	} else if err = r.finalizeObject(instance, patternsOperatorConfig); err != nil {
 		return r.actionPerformed(instance, "finalize", err)
 	} else {
 		log.Printf("Removing finalizer from %s\n", instance.Name)
@@ -200,27 +264,43 @@ func (r *PatternReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 			log.Printf("\x1b[31;1m\tReconcile step %q failed: %s\x1b[0m\n", "remove finalizer", err.Error())
 			return reconcile.Result{}, err
 		}
+		deletePatternMetrics(instance)
 		log.Printf("\x1b[34;1m\tReconcile step %q complete\x1b[0m\n", "finalize")
 		return reconcile.Result{}, nil
 	}
 
 	// Ensure console plugin is registered and enabled
-	if err := console.CreateOrUpdatePlugin(ctx, r.Client); err != nil {
-		r.logger.Error(err, "failed to create/update console plugin")
-	}
-	if err := console.EnablePlugin(ctx, r.Client); err != nil {
-		r.logger.Error(err, "failed to enable console plugin")
+	if platform.IsOpenShift() {
+		if err := console.CreateOrUpdatePlugin(ctx, r.Client); err != nil {
+			r.logger.Error(err, "failed to create/update console plugin")
+		}
+		if err := console.EnablePlugin(ctx, r.Client); err != nil {
+			r.logger.Error(err, "failed to enable console plugin")
+		}
 	}
 
 	// -- Fill in defaults (changes made to a copy and not persisted)
-	qualifiedInstance, err := r.applyDefaults(instance)
+	qualifiedInstance, err := r.applyDefaults(instance, patternsOperatorConfig)
 	if err != nil {
 		return r.actionPerformed(qualifiedInstance, "applying defaults", err)
 	}
+	parameterValues, err := resolveExtraParameters(r.fullClient, qualifiedInstance)
+	if err != nil {
+		return r.actionPerformed(qualifiedInstance, "resolving the extra parameters", err)
+	}
 
-	// -- Detect ArgoCD namespace (legacy upgrade vs greenfield)
-	// Must happen before subscription creation since it controls DISABLE_DEFAULT_ARGOCD_INSTANCE.
-	detectArgoNamespace(r.dynamicClient)
+	// Persisted by the next status update, whichever step it comes from
+	statusChanged := updateSuspendedCondition(qualifiedInstance)
+	if statusChanged {
+		r.recordNormalEvent(qualifiedInstance, EventReasonResumed, "Reconciliation resumed")
+	}
+
+	provisioner, err := newGitOpsProvisioner(patternsOperatorConfig)
+	if err != nil {
+		return r.subsystemActionPerformed(qualifiedInstance, api.GitOpsOperatorReady, "selecting the GitOps provisioner", err)
+	}
+	// -- Detect ArgoCD namespace (legacy upgrade vs greenfield, or the external instance)
+	provisioner.DetectArgoInstance(r)
 
 	if r.AnalyticsClient.SendPatternInstallationInfo(qualifiedInstance) {
 		return r.actionPerformed(qualifiedInstance, "Updated status with identity sent", nil)
@@ -231,61 +311,103 @@ func (r *PatternReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	}
 
 	// -- GitOps Subscription
-	if done, result, subErr := r.reconcileGitOpsSubscription(qualifiedInstance, patternsOperatorConfig); done {
-		return result, subErr
-	}
-	logOnce("subscription found")
-
-	// Dynamically add an ArgoCD watch once the GitOps operator is installed
-	// and the CRD is available. This is a no-op after the first successful call.
+	stepStart := time.Now()
+	done, result, stepErr := provisioner.Reconcile(r, qualifiedInstance)
+	observeReconcileStep(reconcileStepSubscription, stepStart, done, qualifiedInstance)
+	if done {
+		return result, stepErr
+	}
+	logOnce(fmt.Sprintf("%s provisioner ready", provisioner.Name()))
+	statusChanged = setPatternCondition(qualifiedInstance, api.GitOpsOperatorReady, metav1.ConditionTrue, api.ReasonReconciled,
+		provisioner.ReadyMessage()) || statusChanged
+
+	// Dynamically add the ArgoCD and Application watches once the GitOps operator is installed
+	// and the CRDs are available. This is a no-op after the first successful call.
 	r.startArgoCDWatch()
-
-	if done, result, argoErr := r.reconcileArgoInfra(qualifiedInstance, patternsOperatorConfig); done {
-		return result, argoErr
+	r.startApplicationWatch()
+
+	if provisioner.ManagesArgoCD() {
+		stepStart = time.Now()
+		done, result, stepErr = r.reconcileArgoInfra(qualifiedInstance, patternsOperatorConfig)
+		observeReconcileStep(reconcileStepArgoInfra, stepStart, done, qualifiedInstance)
+		if done {
+			return result, stepErr
+		}
+		statusChanged = setPatternCondition(qualifiedInstance, api.ArgoCDReady, metav1.ConditionTrue, api.ReasonReconciled,
+			fmt.Sprintf("ArgoCD instance %s/%s is up to date", getClusterWideArgoNamespace(), getClusterWideArgoName())) || statusChanged
+	} else {
+		statusChanged = setPatternCondition(qualifiedInstance, api.ArgoCDReady, metav1.ConditionTrue, api.ReasonReconciled,
+			fmt.Sprintf("ArgoCD instance %s/%s is managed externally", getClusterWideArgoNamespace(), getClusterWideArgoName())) || statusChanged
 	}
 
-	// Copy the bootstrap secret to the namespaced argo namespace
-	if qualifiedInstance.Spec.GitConfig.TokenSecret != "" {
-		if err = r.copyAuthGitSecret(qualifiedInstance.Spec.GitConfig.TokenSecretNamespace,
-			qualifiedInstance.Spec.GitConfig.TokenSecret, getClusterWideArgoNamespace(), "vp-private-repo-credentials"); err != nil {
-			return r.actionPerformed(qualifiedInstance, "copying clusterwide git auth secret to namespaced argo", err)
-		}
+	// Copy the bootstrap secret to the clusterwide argo namespace
+	if err = r.syncAuthGitSecret(qualifiedInstance, getClusterWideArgoNamespace(), patternsOperatorConfig.getBoolValue(configKeyAllowMultiple)); err != nil {
+		return r.actionPerformed(qualifiedInstance, "copying clusterwide git auth secret to namespaced argo", err)
 	}
 
 	// If you specified OriginRepo then we automatically spawn a gitea instance via a special argo gitea application
 	if qualifiedInstance.Spec.GitConfig.OriginRepo != "" {
+		stepStart = time.Now()
 		giteaErr := r.createGiteaInstance(qualifiedInstance, patternsOperatorConfig)
+		observeReconcileStepError(reconcileStepGitea, stepStart, giteaErr)
 		if giteaErr != nil {
 			return r.actionPerformed(qualifiedInstance, "error created gitea instance", giteaErr)
 		}
 	}
 
-	ret, err := r.getLocalGit(qualifiedInstance)
+	if err = validateRollbackRevision(qualifiedInstance); err != nil {
+		setPatternCondition(qualifiedInstance, api.GitCheckoutReady, metav1.ConditionFalse, api.ReasonInvalidRollbackRevision, err.Error())
+		return r.actionPerformed(qualifiedInstance, "validating rollback revision", err)
+	}
+
+	stepStart = time.Now()
+	ret, err := r.getLocalGit(qualifiedInstance, patternsOperatorConfig)
+	observeReconcileStepError(reconcileStepGitCheckout, stepStart, err)
 	if err != nil {
 		// Handle validation errors with appropriate status conditions
 		if ret == "prerequisite validation" && strings.Contains(err.Error(), "required values file not found") {
 			// Set Missing condition for missing values files
-			setPatternCondition(qualifiedInstance, api.Missing, corev1.ConditionTrue, err.Error())
+			setPatternCondition(qualifiedInstance, api.Missing, metav1.ConditionTrue, api.ReasonValuesFileMissing, err.Error())
+			setPatternCondition(qualifiedInstance, api.GitCheckoutReady, metav1.ConditionFalse, api.ReasonValuesFileMissing, err.Error())
+		} else if isSSHHostKeyError(err) {
+			removePatternCondition(qualifiedInstance, api.Missing)
+			setPatternCondition(qualifiedInstance, api.GitCheckoutReady, metav1.ConditionFalse, api.ReasonHostKeyVerificationFailed, err.Error())
+		} else if isSignatureVerificationError(err) {
+			removePatternCondition(qualifiedInstance, api.Missing)
+			setPatternCondition(qualifiedInstance, api.GitCheckoutReady, metav1.ConditionFalse, api.ReasonSignatureVerificationFailed, err.Error())
 		} else {
 			// Clear Missing condition for other types of errors
 			removePatternCondition(qualifiedInstance, api.Missing)
+			setPatternCondition(qualifiedInstance, api.GitCheckoutReady, metav1.ConditionFalse, api.ReasonReconcileFailed, err.Error())
 		}
 		return r.actionPerformed(qualifiedInstance, ret, err)
 	}
 
 	// Clear Missing condition on successful validation
-	removePatternCondition(qualifiedInstance, api.Missing)
+	statusChanged = removePatternCondition(qualifiedInstance, api.Missing) || statusChanged
+	statusChanged = setPatternCondition(qualifiedInstance, api.GitCheckoutReady, metav1.ConditionTrue, api.ReasonReconciled,
+		fmt.Sprintf("Checked out %s", getTargetRevision(qualifiedInstance))) || statusChanged
 
-	if done, result, appErr := r.reconcileApplication(qualifiedInstance); done {
-		return result, appErr
+	namespacesChanged, err := r.reconcileNamespaceConflicts(qualifiedInstance)
+	if err != nil {
+		return r.actionPerformed(qualifiedInstance, "detecting namespace conflicts", err)
 	}
 
+	stepStart = time.Now()
+	done, result, stepErr = r.reconcileApplication(qualifiedInstance, parameterValues)
+	observeReconcileStep(reconcileStepApplication, stepStart, done, qualifiedInstance)
+	if done {
+		return result, stepErr
+	}
+
+	// Record the deployed commit and compare it with the one Argo last synced
+	statusChanged = recordRevisionHistory(qualifiedInstance) || namespacesChanged || statusChanged
+	statusChanged = updateRolledBackCondition(qualifiedInstance) || statusChanged
+	statusChanged = r.reconcileApplicationConditions(qualifiedInstance) || statusChanged
+
 	// Copy the bootstrap secret to the namespaced argo namespace
-	if qualifiedInstance.Spec.GitConfig.TokenSecret != "" {
-		if err = r.copyAuthGitSecret(qualifiedInstance.Spec.GitConfig.TokenSecretNamespace,
-			qualifiedInstance.Spec.GitConfig.TokenSecret, applicationName(qualifiedInstance), "vp-private-repo-credentials"); err != nil {
-			return r.actionPerformed(qualifiedInstance, "copying clusterwide git auth secret to namespaced argo", err)
-		}
+	if err = r.syncAuthGitSecret(qualifiedInstance, applicationName(qualifiedInstance), patternsOperatorConfig.getBoolValue(configKeyAllowMultiple)); err != nil {
+		return r.actionPerformed(qualifiedInstance, "copying clusterwide git auth secret to namespaced argo", err)
 	}
 	// Perform validation of the site values file(s)
 	if err = r.postValidation(qualifiedInstance); err != nil {
@@ -305,48 +427,45 @@ func (r *PatternReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 
 	log.Printf("\x1b[32;1m\tReconcile complete\x1b[0m\n")
 
-	if qualifiedInstance.Status.LastStep != "reconcile complete" || qualifiedInstance.Status.LastError != "" {
+	statusChanged = updateReadyCondition(qualifiedInstance) || statusChanged
+
+	if statusChanged || qualifiedInstance.Status.LastStep != "reconcile complete" || qualifiedInstance.Status.LastError != "" ||
+		qualifiedInstance.Status.ConsecutiveFailures != 0 {
 		qualifiedInstance.Status.LastStep = "reconcile complete"
 		qualifiedInstance.Status.LastError = ""
+		qualifiedInstance.Status.ConsecutiveFailures = 0
 		if updateErr := r.Client.Status().Update(context.TODO(), qualifiedInstance); updateErr != nil {
 			r.logger.Error(updateErr, "Failed to update Pattern status")
 		}
 	}
 
-	result := ctrl.Result{
+	return ctrl.Result{
 		Requeue:      false,
-		RequeueAfter: ReconcileLoopRequeueTime,
-	}
-
-	return result, nil
+		RequeueAfter: reconcileInterval(qualifiedInstance),
+	}, nil
 }
 
 // reconcileGitOpsSubscription ensures the GitOps operator subscription exists and is up-to-date.
 // It returns (done, result, err) — when done is true the caller should return result/err immediately.
-func (r *PatternReconciler) reconcileGitOpsSubscription(qualifiedInstance *api.Pattern, patternsOperatorConfig PatternsOperatorConfig) (done bool, result ctrl.Result, err error) {
-	// Only disable the default ArgoCD instance for non-legacy deployments.
-	// For legacy deployments, the gitops-operator's default instance is still in use.
-	disableDefault := !isLegacyArgoNamespace()
-	targetSub := newSubscription(patternsOperatorConfig, disableDefault)
-
-	subscriptionName, subscriptionNamespace := DetectGitOpsSubscription()
-	// If the pattern operator is installed to the new vp namespace we need to create a ns, operatorgroup for the new sub
-	if DetectOperatorNamespace() != LegacyOperatorNamespace {
+func (r *PatternReconciler) reconcileGitOpsSubscription(qualifiedInstance *api.Pattern, targetSub *olmapi.Subscription) (done bool, result ctrl.Result, err error) {
+	subscriptionName, subscriptionNamespace := targetSub.Name, targetSub.Namespace
+	// Outside of the legacy openshift-operators namespace we need to create a ns, operatorgroup for the new sub
+	if subscriptionNamespace != LegacyOperatorNamespace {
 		// Create namespace for gitops subscription
 		if err := createNamespace(r.fullClient, subscriptionNamespace); err != nil {
-			res, e := r.actionPerformed(qualifiedInstance, "error creating namespace for gitops subscription", err)
+			res, e := r.subsystemActionPerformed(qualifiedInstance, api.GitOpsOperatorReady, "error creating namespace for gitops subscription", err)
 			return true, res, e
 		}
 
 		// Create operatorgroup for gitops subscription
 		var og *v1.OperatorGroup
 		if og, err = getOperatorGroup(r.olmClient, subscriptionNamespace); err != nil {
-			res, e := r.actionPerformed(qualifiedInstance, "error getting operatorgroup for gitops subscription", err)
+			res, e := r.subsystemActionPerformed(qualifiedInstance, api.GitOpsOperatorReady, "error getting operatorgroup for gitops subscription", err)
 			return true, res, e
 		}
 		if og == nil {
 			if err := createOperatorGroup(r.olmClient, subscriptionNamespace); err != nil {
-				res, e := r.actionPerformed(qualifiedInstance, "error creating operatorgroup for gitops subscription", err)
+				res, e := r.subsystemActionPerformed(qualifiedInstance, api.GitOpsOperatorReady, "error creating operatorgroup for gitops subscription", err)
 				return true, res, e
 			}
 		}
@@ -354,15 +473,16 @@ func (r *PatternReconciler) reconcileGitOpsSubscription(qualifiedInstance *api.P
 
 	currentSub, err := getSubscription(r.olmClient, subscriptionName, subscriptionNamespace)
 	if err != nil {
-		res, e := r.actionPerformed(qualifiedInstance, "error getting gitops subscription", err)
+		res, e := r.subsystemActionPerformed(qualifiedInstance, api.GitOpsOperatorReady, "error getting gitops subscription", err)
 		return true, res, e
 	}
 
 	if currentSub == nil {
 		if err = createSubscription(r.olmClient, targetSub); err != nil {
-			res, e := r.actionPerformed(qualifiedInstance, "error creating gitops subscription", err)
+			res, e := r.subsystemActionPerformed(qualifiedInstance, api.GitOpsOperatorReady, "error creating gitops subscription", err)
 			return true, res, e
 		}
+		r.recordNormalEvent(qualifiedInstance, EventReasonSubscriptionCreated, "Created subscription %s/%s", targetSub.Namespace, targetSub.Name)
 	} else {
 		// Remove any stale owner references from the subscription (historically set by
 		// the pattern or the operator configmap). Cross-namespace owner references are
@@ -380,17 +500,20 @@ func (r *PatternReconciler) reconcileGitOpsSubscription(qualifiedInstance *api.P
 		}
 		if changed {
 			if _, err := r.olmClient.OperatorsV1alpha1().Subscriptions(currentSub.Namespace).Update(context.Background(), currentSub, metav1.UpdateOptions{}); err != nil {
-				res, e := r.actionPerformed(qualifiedInstance, "error removing stale owner references from gitops subscription", err)
+				res, e := r.subsystemActionPerformed(qualifiedInstance, api.GitOpsOperatorReady, "error removing stale owner references from gitops subscription", err)
 				return true, res, e
 			}
-			res, e := r.actionPerformed(qualifiedInstance, "removed stale owner references from gitops subscription", nil)
+			res, e := r.subsystemActionPerformed(qualifiedInstance, api.GitOpsOperatorReady, "removed stale owner references from gitops subscription", nil)
 			return true, res, e
 		}
 
 		// Check version/channel etc
 		updatedSub, errSub := updateSubscription(r.olmClient, targetSub, currentSub)
 		if updatedSub {
-			res, e := r.actionPerformed(qualifiedInstance, "update gitops subscription", errSub)
+			if errSub == nil {
+				r.recordNormalEvent(qualifiedInstance, EventReasonSubscriptionUpdated, "Updated subscription %s/%s", currentSub.Namespace, currentSub.Name)
+			}
+			res, e := r.subsystemActionPerformed(qualifiedInstance, api.GitOpsOperatorReady, "update gitops subscription", errSub)
 			return true, res, e
 		}
 	}
@@ -405,42 +528,63 @@ func (r *PatternReconciler) reconcileArgoInfra(qualifiedInstance *api.Pattern, p
 	clusterWideNS := getClusterWideArgoNamespace()
 	if !haveNamespace(r.Client, clusterWideNS) {
 		if isLegacyArgoNamespace() {
-			res, e := r.actionPerformed(qualifiedInstance, "check application namespace", fmt.Errorf("waiting for creation"))
+			res, e := r.subsystemActionPerformed(qualifiedInstance, api.ArgoCDReady, "check application namespace", newWaitingError("waiting for creation"))
 			return true, res, e
 		}
 		if nsErr := createNamespace(r.fullClient, clusterWideNS); nsErr != nil {
-			res, e := r.actionPerformed(qualifiedInstance, "error creating ArgoCD namespace", nsErr)
+			res, e := r.subsystemActionPerformed(qualifiedInstance, api.ArgoCDReady, "error creating ArgoCD namespace", nsErr)
 			return true, res, e
 		}
-		res, e := r.actionPerformed(qualifiedInstance, "created ArgoCD namespace", nil)
+		res, e := r.subsystemActionPerformed(qualifiedInstance, api.ArgoCDReady, "created ArgoCD namespace", nil)
 		return true, res, e
 	}
 	logOnce("namespace found")
 
-	if errCABundle := createTrustedBundleCM(r.fullClient, clusterWideNS); errCABundle != nil {
-		res, e := r.actionPerformed(qualifiedInstance, "error while creating trustedbundle cm", errCABundle)
-		return true, res, e
-	}
+	openShift := r.isOpenShift()
+	// The CA bundle is injected by the OpenShift cluster network operator
+	if openShift {
+		if errCABundle := createTrustedBundleCM(r.fullClient, clusterWideNS); errCABundle != nil {
+			res, e := r.subsystemActionPerformed(qualifiedInstance, api.ArgoCDReady, "error while creating trustedbundle cm", errCABundle)
+			return true, res, e
+		}
 
-	populated, errPopulated := isTrustedBundleCMPopulated(r.fullClient, clusterWideNS)
-	if errPopulated != nil {
-		res, e := r.actionPerformed(qualifiedInstance, "error checking trusted-ca-bundle population", errPopulated)
-		return true, res, e
-	}
-	if !populated {
-		res, e := r.actionPerformed(qualifiedInstance, "waiting for trusted-ca-bundle to be populated",
-			fmt.Errorf("trusted-ca-bundle configmap in %s not yet populated by cluster network operator", clusterWideNS))
-		return true, res, e
+		populated, errPopulated := isTrustedBundleCMPopulated(r.fullClient, clusterWideNS)
+		if errPopulated != nil {
+			res, e := r.subsystemActionPerformed(qualifiedInstance, api.ArgoCDReady, "error checking trusted-ca-bundle population", errPopulated)
+			return true, res, e
+		}
+		if !populated {
+			res, e := r.subsystemActionPerformed(qualifiedInstance, api.ArgoCDReady, "waiting for trusted-ca-bundle to be populated",
+				newWaitingError("trusted-ca-bundle configmap in %s not yet populated by cluster network operator", clusterWideNS))
+			return true, res, e
+		}
 	}
 
-	if argoErr := createOrUpdateArgoCD(r.dynamicClient, r.fullClient, getClusterWideArgoName(), clusterWideNS, patternsOperatorConfig); argoErr != nil {
-		res, e := r.actionPerformed(qualifiedInstance, "created or updated clusterwide argo instance", argoErr)
-		return true, res, e
+	// Upstream Argo CD installed without the operator has no ArgoCD CRD, the instance is managed by whoever installed it
+	haveArgoCDCRD := openShift
+	if !openShift {
+		var apiErr error
+		if haveArgoCDCRD, apiErr = haveAPIVersion(r.fullClient, ArgoCDGroup, ArgoCDVersion); apiErr != nil {
+			res, e := r.subsystemActionPerformed(qualifiedInstance, api.ArgoCDReady, "checking the ArgoCD API", apiErr)
+			return true, res, e
+		}
+		if !haveArgoCDCRD {
+			logOnce(fmt.Sprintf("API version %s/%s is not available, not managing the ArgoCD instance", ArgoCDGroup, ArgoCDVersion))
+		}
+	}
+	if haveArgoCDCRD {
+		argoChanged, argoErr := createOrUpdateArgoCD(r.dynamicClient, r.fullClient, getClusterWideArgoName(), clusterWideNS, patternsOperatorConfig)
+		if argoErr != nil {
+			res, e := r.subsystemActionPerformed(qualifiedInstance, api.ArgoCDReady, "created or updated clusterwide argo instance", argoErr)
+			return true, res, e
+		} else if argoChanged {
+			r.recordNormalEvent(qualifiedInstance, EventReasonArgoCDUpdated, "Created or updated ArgoCD instance %s/%s", clusterWideNS, getClusterWideArgoName())
+		}
 	}
 
-	if !isLegacyArgoNamespace() && qualifiedInstance.Status.AppClusterDomain != "" {
+	if openShift && !isLegacyArgoNamespace() && qualifiedInstance.Status.AppClusterDomain != "" {
 		if clErr := createOrUpdateConsoleLink(r.dynamicClient, getClusterWideArgoName(), clusterWideNS, qualifiedInstance.Status.AppClusterDomain); clErr != nil {
-			res, e := r.actionPerformed(qualifiedInstance, "error creating ConsoleLink for ArgoCD", clErr)
+			res, e := r.subsystemActionPerformed(qualifiedInstance, api.ArgoCDReady, "error creating ConsoleLink for ArgoCD", clErr)
 			return true, res, e
 		}
 	}
@@ -450,14 +594,18 @@ func (r *PatternReconciler) reconcileArgoInfra(qualifiedInstance *api.Pattern, p
 
 // reconcileApplication ensures the ArgoCD Application for the pattern is created or updated.
 // Returns (done, result, err) — when done is true the caller should return result/err immediately.
-func (r *PatternReconciler) reconcileApplication(qualifiedInstance *api.Pattern) (done bool, result ctrl.Result, err error) {
+func (r *PatternReconciler) reconcileApplication(qualifiedInstance *api.Pattern,
+	parameterValues map[string]string) (done bool, result ctrl.Result, err error) {
 	clusterWideNS := getClusterWideArgoNamespace()
-	targetApp := newArgoApplication(qualifiedInstance)
+	targetApp := newArgoApplication(qualifiedInstance, parameterValues)
 	_ = controllerutil.SetOwnerReference(qualifiedInstance, targetApp, r.Scheme)
 	app, appErr := getApplication(r.argoClient, applicationName(qualifiedInstance), clusterWideNS)
 	if app == nil {
 		log.Printf("App not found: %s\n", appErr.Error())
 		createErr := createApplication(r.argoClient, targetApp, clusterWideNS)
+		if createErr == nil {
+			r.recordNormalEvent(qualifiedInstance, EventReasonApplicationCreated, "Created application %s/%s", clusterWideNS, targetApp.Name)
+		}
 		res, e := r.actionPerformed(qualifiedInstance, "create application", createErr)
 		return true, res, e
 	} else if ownedBySame(targetApp, app) {
@@ -466,7 +614,10 @@ func (r *PatternReconciler) reconcileApplication(qualifiedInstance *api.Pattern)
 			if errApp != nil {
 				qualifiedInstance.Status.Version = 1 + qualifiedInstance.Status.Version
 			}
-			_ = DropLocalGitPaths()
+			_ = DropLocalGitPaths(qualifiedInstance.Namespace, qualifiedInstance.Name)
+			if errApp == nil {
+				r.recordNormalEvent(qualifiedInstance, EventReasonApplicationUpdated, "Updated application %s/%s", clusterWideNS, targetApp.Name)
+			}
 			res, e := r.actionPerformed(qualifiedInstance, "updated application", errApp)
 			return true, res, e
 		}
@@ -477,7 +628,189 @@ func (r *PatternReconciler) reconcileApplication(qualifiedInstance *api.Pattern)
 	return false, ctrl.Result{}, nil
 }
 
+// reconcileApplicationConditions reports the sync and health status of the app-of-apps and compares the
+// commit the target revision resolved to with the commit Argo last synced. Returns true if the conditions changed.
+func (r *PatternReconciler) reconcileApplicationConditions(p *api.Pattern) bool {
+	app, err := getApplication(r.argoClient, applicationName(p), getClusterWideArgoNamespace())
+	if err != nil {
+		log.Printf("Could not get application %s: %v", applicationName(p), err)
+		return updateApplicationConditions(p, nil)
+	}
+	changed := updateApplicationConditions(p, app)
+	if p.Status.Revision == nil {
+		return changed
+	}
+	return updateGitSyncConditions(p, p.Status.Revision.Revision, getSyncedRevision(app, p.Spec.GitConfig.TargetRepo)) || changed
+}
+
+// reconcileNamespaceConflicts records the namespaces the pattern deploys to and fails when a pattern
+// created before it already deploys to one of them, or has the same name. Argo applications and labels only
+// carry the name of a pattern. Returns true if the recorded namespaces changed
+func (r *PatternReconciler) reconcileNamespaceConflicts(p *api.Pattern) (bool, error) {
+	values, err := getCheckoutHelmValues(p)
+	if err != nil {
+		return false, err
+	}
+	namespaces := getClusterGroupNamespaces(values)
+	changed := !slices.Equal(p.Status.Namespaces, namespaces)
+	p.Status.Namespaces = namespaces
+
+	var patterns api.PatternList
+	if err := r.List(context.TODO(), &patterns); err != nil {
+		return changed, fmt.Errorf("failed to list Pattern resources: %w", err)
+	}
+	for i := range patterns.Items {
+		other := &patterns.Items[i]
+		if (other.Namespace == p.Namespace && other.Name == p.Name) || !createdBefore(other, p) {
+			continue
+		}
+		if other.Name == p.Name {
+			return changed, fmt.Errorf("pattern %s/%s has the same name, pattern names must be unique in the cluster",
+				other.Namespace, other.Name)
+		}
+		var shared []string
+		for _, ns := range other.Status.Namespaces {
+			if slices.Contains(namespaces, ns) {
+				shared = append(shared, ns)
+			}
+		}
+		if len(shared) > 0 {
+			return changed, fmt.Errorf("namespaces %s are already deployed by pattern %s/%s",
+				strings.Join(shared, ", "), other.Namespace, other.Name)
+		}
+	}
+	return changed, nil
+}
+
+// createdBefore returns true if pattern a was created before pattern b, breaking ties by namespace and name
+func createdBefore(a, b *api.Pattern) bool {
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	if a.Namespace != b.Namespace {
+		return a.Namespace < b.Namespace
+	}
+	return a.Name < b.Name
+}
+
+// validateRollbackRevision makes sure a pinned rollback revision is one the pattern was previously deployed at
+func validateRollbackRevision(p *api.Pattern) error {
+	rollback := p.Spec.GitConfig.RollbackRevision
+	if rollback == "" {
+		return nil
+	}
+	for i := range p.Status.RevisionHistory {
+		if p.Status.RevisionHistory[i].Revision == rollback {
+			return nil
+		}
+	}
+	return fmt.Errorf("rollback revision %s is not in the revision history of the pattern", rollback)
+}
+
+// updateRolledBackCondition sets the RolledBack condition while a rollback revision is pinned and
+// removes it once the pin is cleared. Returns true if the conditions changed.
+func updateRolledBackCondition(p *api.Pattern) bool {
+	rollback := p.Spec.GitConfig.RollbackRevision
+	if rollback == "" {
+		return removePatternCondition(p, api.RolledBack)
+	}
+	message := fmt.Sprintf("Pinned to commit %s instead of target revision %q", rollback, p.Spec.GitConfig.TargetRevision)
+	return setPatternCondition(p, api.RolledBack, metav1.ConditionTrue, api.ReasonRollbackPinned, message)
+}
+
+// isPatternSuspended reports whether reconciliation was paused with spec.suspend or the suspend annotation
+func isPatternSuspended(p *api.Pattern) bool {
+	return p.Spec.Suspend || strings.EqualFold(p.Annotations[api.SuspendAnnotation], "true")
+}
+
+// updateSuspendedCondition sets or clears the Suspended condition. Returns true if it changed
+func updateSuspendedCondition(p *api.Pattern) bool {
+	if !isPatternSuspended(p) {
+		return removePatternCondition(p, api.Suspended)
+	}
+	_, existing := getPatternConditionByType(p.Status.Conditions, api.Suspended)
+	if existing != nil && existing.Status == metav1.ConditionTrue {
+		return false
+	}
+	setPatternCondition(p, api.Suspended, metav1.ConditionTrue, api.ReasonSuspended,
+		"Reconciliation is suspended, the ArgoCD instance, applications, subscriptions and secrets are not updated")
+	setPatternCondition(p, api.Ready, metav1.ConditionUnknown, api.ReasonSuspended, "Reconciliation is suspended")
+	return true
+}
+
+// reconcileSuspended only refreshes the status of a suspended pattern, without touching anything
+// the operator deployed so that manual fixes are not reverted
+func (r *PatternReconciler) reconcileSuspended(p *api.Pattern) (reconcile.Result, error) {
+	log.Printf("\x1b[33;1m\tReconciliation of %s is suspended\x1b[0m\n", p.Name)
+	statusChanged := updateSuspendedCondition(p)
+	if statusChanged {
+		r.recordNormalEvent(p, EventReasonSuspended, "Reconciliation suspended")
+	}
+
+	if _, err := r.updatePatternCRDetails(p); err != nil {
+		return r.actionPerformed(p, "updating application status while suspended", err)
+	}
+
+	if statusChanged || p.Status.LastStep != "reconciliation suspended" || p.Status.LastError != "" || p.Status.ConsecutiveFailures != 0 {
+		p.Status.LastStep = "reconciliation suspended"
+		p.Status.LastError = ""
+		p.Status.ConsecutiveFailures = 0
+		if err := r.Client.Status().Update(context.TODO(), p); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+	return reconcile.Result{RequeueAfter: reconcileInterval(p)}, nil
+}
+
+// recordRevisionHistory prepends the current revision to the revision history when the pattern
+// gets deployed at a new commit, keeping at most RevisionHistoryLimit entries.
+// Returns true if the history changed.
+func recordRevisionHistory(p *api.Pattern) bool {
+	if p.Status.Revision == nil {
+		return false
+	}
+	if len(p.Status.RevisionHistory) > 0 && p.Status.RevisionHistory[0].Revision == p.Status.Revision.Revision {
+		return false
+	}
+	history := append([]api.PatternRevision{*p.Status.Revision}, p.Status.RevisionHistory...)
+	if len(history) > RevisionHistoryLimit {
+		history = history[:RevisionHistoryLimit]
+	}
+	p.Status.RevisionHistory = history
+	return true
+}
+
+// updateGitSyncConditions sets GitInSync when the commit Argo synced matches the one the target
+// revision resolved to, and GitOutOfSync otherwise. Only one of the two is kept on the pattern.
+// Returns true if the conditions changed.
+func updateGitSyncConditions(p *api.Pattern, resolved, synced string) bool {
+	if resolved == "" {
+		return false
+	}
+	conditionType, staleType := api.GitInSync, api.GitOutOfSync
+	message := fmt.Sprintf("Argo synced commit %s, target revision %q resolves to %s",
+		synced, getTargetRevision(p), resolved)
+	if synced != resolved {
+		conditionType, staleType = api.GitOutOfSync, api.GitInSync
+		if synced == "" {
+			message = fmt.Sprintf("Argo has not synced any commit yet, target revision %q resolves to %s",
+				getTargetRevision(p), resolved)
+		}
+	}
+
+	reason := api.ReasonCommitSynced
+	if conditionType == api.GitOutOfSync {
+		reason = api.ReasonCommitNotSynced
+	}
+	changed := setPatternCondition(p, conditionType, metav1.ConditionTrue, reason, message)
+	return removePatternCondition(p, staleType) || changed
+}
+
 func (r *PatternReconciler) createGiteaInstance(input *api.Pattern, patternsOperatorConfig PatternsOperatorConfig) error {
+	// The migration goes through the route of the gitea server
+	if !r.isOpenShift() {
+		return fmt.Errorf("the in-cluster gitea server requires OpenShift routes, set spec.gitConfig.targetRepo without spec.gitConfig.originRepo")
+	}
 	gitConfig := input.Spec.GitConfig
 	clusterWideNS := getClusterWideArgoNamespace()
 	// The reason we create the vp-gitea namespace and and the
@@ -513,8 +846,13 @@ func (r *PatternReconciler) createGiteaInstance(input *api.Pattern, patternsOper
 	app, err := getApplication(r.argoClient, GiteaApplicationName, clusterWideNS)
 	if app == nil {
 		log.Printf("Gitea app not found: %s\n", err.Error())
-		err = createApplication(r.argoClient, giteaApp, clusterWideNS)
-		return fmt.Errorf("create gitea application: %v", err)
+		if err = createApplication(r.argoClient, giteaApp, clusterWideNS); err != nil {
+			return fmt.Errorf("create gitea application: %w", err)
+		}
+		return newWaitingError("created gitea application")
+	} else if owner := app.Labels[PatternApplicationLabel]; owner != "" && owner != input.Name {
+		// The in-cluster git server is shared, only the pattern that deployed it manages its application
+		logOnce(fmt.Sprintf("Using the in-cluster git server deployed by pattern %s", owner))
 	} else if ownedBySame(giteaApp, app) {
 		// Check values
 		changed, errApp := updateApplication(r.argoClient, giteaApp, app, clusterWideNS)
@@ -522,22 +860,27 @@ func (r *PatternReconciler) createGiteaInstance(input *api.Pattern, patternsOper
 			if errApp != nil {
 				input.Status.Version = 1 + input.Status.Version
 			}
-			_ = DropLocalGitPaths()
+			_ = DropLocalGitPaths(input.Namespace, input.Name)
 
-			return fmt.Errorf("updated gitea application: %v", errApp)
+			if errApp != nil {
+				return fmt.Errorf("updated gitea application: %w", errApp)
+			}
+			return newWaitingError("updated gitea application")
 		}
 	} else {
 		// Someone manually removed the owner ref
 		return fmt.Errorf("we no longer own Application %q", giteaApp.Name)
 	}
 	if !haveNamespace(r.Client, GiteaNamespace) {
-		return fmt.Errorf("waiting for giteanamespace creation")
+		return newWaitingError("waiting for giteanamespace creation")
 	}
 
 	// Here we need to call the gitea migration bits
 	// Let's get the GiteaServer route
 	giteaRouteURL, routeErr := getRoute(r.routeClient, GiteaRouteName, GiteaNamespace)
-	if routeErr != nil {
+	if kerrors.IsNotFound(routeErr) {
+		return newWaitingError("waiting for the GiteaServer route: %v", routeErr)
+	} else if routeErr != nil {
 		return fmt.Errorf("GiteaServer route not ready: %v", routeErr)
 	}
 	// Extract the repository name from the original target repo
@@ -561,14 +904,25 @@ func (r *PatternReconciler) createGiteaInstance(input *api.Pattern, patternsOper
 		return fmt.Errorf("GiteaServer Migrate Repository Error: %v", err)
 	}
 
+	// The migration is a no-op once the repository exists in gitea
+	if gitConfig.TargetRepo == giteaRepoURL {
+		return nil
+	}
+
 	// Migrate Repo has been done.
 	// Replace the Target Repo with new Gitea Repo URL
-	// and update the pattern CR
-	input.Spec.GitConfig.TargetRepo = giteaRepoURL
-	err = r.Update(context.Background(), input)
-	if err != nil {
+	// and update the pattern CR. Only the target repo is written back, input also holds the defaults
+	current := &api.Pattern{}
+	if err = r.Get(context.Background(), client.ObjectKeyFromObject(input), current); err != nil {
+		return fmt.Errorf("update CR Target Repo: %v", err)
+	}
+	current.Spec.GitConfig.TargetRepo = giteaRepoURL
+	if err = r.Update(context.Background(), current); err != nil {
 		return fmt.Errorf("update CR Target Repo: %v", err)
 	}
+	input.Spec.GitConfig.TargetRepo = giteaRepoURL
+	input.ResourceVersion = current.ResourceVersion
+	r.recordNormalEvent(input, EventReasonGiteaMigrated, "Migrated %s to the in-cluster git server at %s", gitConfig.OriginRepo, giteaRepoURL)
 
 	return nil
 }
@@ -589,6 +943,11 @@ func (r *PatternReconciler) preValidation(input *api.Pattern) error {
 		return fmt.Errorf("TargetRepo cannot be empty")
 	}
 
+	// Deploying without the structured values would silently drop the user's settings
+	if _, err := getPatternValues(input); err != nil {
+		return err
+	}
+
 	// Validate that the required values file exists for the cluster group
 	if input.Spec.ClusterGroupName != "" && input.Status.LocalCheckoutPath != "" {
 		var valuesFile, displayName string
@@ -616,66 +975,24 @@ func (r *PatternReconciler) postValidation(input *api.Pattern) error { //nolint:
 	return nil
 }
 
-func (r *PatternReconciler) applyDefaults(input *api.Pattern) (*api.Pattern, error) {
+func (r *PatternReconciler) applyDefaults(input *api.Pattern, patternsOperatorConfig PatternsOperatorConfig) (*api.Pattern, error) {
 	output := input.DeepCopy()
 
-	// Cluster ID:
-	// oc get clusterversion -o jsonpath='{.items[].spec.clusterID}{"\n"}'
-	// oc get clusterversion/version -o jsonpath='{.spec.clusterID}'
-	if cv, err := r.configClient.ConfigV1().ClusterVersions().Get(context.Background(), "version", metav1.GetOptions{}); err != nil {
-		return output, err
-	} else {
-		output.Status.ClusterID = string(cv.Spec.ClusterID)
-	}
-
-	// Cluster platform
-	// oc get Infrastructure.config.openshift.io/cluster  -o jsonpath='{.spec.platformSpec.type}'
-	clusterInfra, err := r.configClient.ConfigV1().Infrastructures().Get(context.Background(), searchFilterCluster, metav1.GetOptions{})
+	platform, err := r.clusterPlatform()
 	if err != nil {
 		return output, err
-	} else {
-		//   status:
-		//    apiServerInternalURI: https://api-int.beekhof49.blueprints.rhecoeng.com:6443
-		//    apiServerURL: https://api.beekhof49.blueprints.rhecoeng.com:6443
-		//    controlPlaneTopology: HighlyAvailable
-		//    etcdDiscoveryDomain: ""
-		//    infrastructureName: beekhof49-pqzfb
-		//    infrastructureTopology: HighlyAvailable
-		//    platform: AWS
-		//    platformStatus:
-		//      aws:
-		//        region: ap-southeast-2
-		//      type: AWS
-
-		output.Status.ClusterPlatform = string(clusterInfra.Spec.PlatformSpec.Type)
-	}
-
-	// Cluster Version
-	// oc get clusterversion/version -o yaml
-	clusterVersions, err := r.configClient.ConfigV1().ClusterVersions().Get(context.Background(), "version", metav1.GetOptions{})
-	if err != nil {
-		return output, err
-	} else {
-		v, version_err := getCurrentClusterVersion(clusterVersions)
-		if version_err != nil {
-			return output, version_err
-		}
-		output.Status.ClusterVersion = fmt.Sprintf("%d.%d", v.Major(), v.Minor())
 	}
-
-	// Derive cluster and domain names
-	// oc get Ingress.config.openshift.io/cluster -o jsonpath='{.spec.domain}'
-	clusterIngress, err := r.configClient.ConfigV1().Ingresses().Get(context.Background(), searchFilterCluster, metav1.GetOptions{})
+	// Cluster ID, platform, version, and the cluster and domain names
+	clusterInfo, err := getClusterInfo(context.Background(), platform, patternsOperatorConfig)
 	if err != nil {
 		return output, err
 	}
-
-	// "apps.mycluster.blueprints.rhecoeng.com"
-	ss := strings.Split(clusterIngress.Spec.Domain, ".")
-
-	output.Status.ClusterName = ss[1]
-	output.Status.AppClusterDomain = clusterIngress.Spec.Domain
-	output.Status.ClusterDomain = strings.Join(ss[1:], ".")
+	output.Status.ClusterID = clusterInfo.ID
+	output.Status.ClusterPlatform = clusterInfo.Platform
+	output.Status.ClusterVersion = clusterInfo.Version
+	output.Status.ClusterName = clusterInfo.Name
+	output.Status.AppClusterDomain = clusterInfo.AppDomain
+	output.Status.ClusterDomain = clusterInfo.Domain
 
 	if output.Spec.GitOpsConfig == nil {
 		output.Spec.GitOpsConfig = &api.GitOpsConfig{}
@@ -712,9 +1029,9 @@ func (r *PatternReconciler) applyDefaults(input *api.Pattern) (*api.Pattern, err
 		output.Spec.MultiSourceConfig.HelmRepoUrl = GiteaHelmRepoUrl
 	}
 
-	localCheckoutPath := getLocalGitPath(output.Spec.GitConfig.TargetRepo)
+	localCheckoutPath := getLocalGitPath(output.Namespace, output.Name, output.Spec.GitConfig.TargetRepo)
 	if localCheckoutPath != output.Status.LocalCheckoutPath {
-		_ = DropLocalGitPaths()
+		_ = DropLocalGitPaths(output.Namespace, output.Name)
 	}
 	output.Status.LocalCheckoutPath = localCheckoutPath
 
@@ -723,10 +1040,14 @@ func (r *PatternReconciler) applyDefaults(input *api.Pattern) (*api.Pattern, err
 
 func (r *PatternReconciler) updateDeletionPhase(instance *api.Pattern, phase api.PatternDeletionPhase) error {
 	log.Printf("Updating deletion phase to '%s'", phase)
+	previous := instance.Status.DeletionPhase
 	instance.Status.DeletionPhase = phase
+	updateDeletionCondition(instance)
 	if err := r.Client.Status().Update(context.TODO(), instance); err != nil {
 		return fmt.Errorf("failed to update deletion phase: %w", err)
 	}
+	setDeletionPhaseMetric(instance)
+	r.recordNormalEvent(instance, EventReasonDeletionPhaseChanged, "Deletion phase changed from %q to %q", previous, phase)
 
 	// Re-fetch to get updated status
 	if err := r.Get(context.TODO(), client.ObjectKeyFromObject(instance), instance); err != nil {
@@ -745,7 +1066,7 @@ func (r *PatternReconciler) deleteSpokeApps(targetApp, app *argoapi.Application,
 		return fmt.Errorf("failed to update application %q for spoke child deletion: %v", app.Name, errUpdate)
 	}
 	if changed {
-		return fmt.Errorf("updated application %q for spoke child deletion", app.Name)
+		return newWaitingError("updated application %q for spoke child deletion", app.Name)
 	}
 
 	if err := syncApplication(r.argoClient, app, false); err != nil {
@@ -770,7 +1091,7 @@ func (r *PatternReconciler) deleteSpokeApps(targetApp, app *argoapi.Application,
 	}
 
 	if !allGone {
-		return fmt.Errorf("waiting for child applications to be deleted from spoke clusters")
+		return newWaitingError("waiting for child applications to be deleted from spoke clusters")
 	}
 
 	return nil
@@ -803,7 +1124,7 @@ func (r *PatternReconciler) deleteHubApps(targetApp, app *argoapi.Application, n
 
 			if deletedCount > 0 {
 				log.Printf("Deleted %d managed cluster(s), waiting for them to be fully removed", deletedCount)
-				return fmt.Errorf("deleted %d managed cluster(s), waiting for removal to complete before proceeding with hub deletion", deletedCount)
+				return newWaitingError("deleted %d managed cluster(s), waiting for removal to complete before proceeding with hub deletion", deletedCount)
 			}
 		}
 	}
@@ -814,33 +1135,43 @@ func (r *PatternReconciler) deleteHubApps(targetApp, app *argoapi.Application, n
 		return fmt.Errorf("failed to update application %q for hub deletion: %v", app.Name, errUpdate)
 	}
 	if changed {
-		return fmt.Errorf("updated application %q for hub deletion", app.Name)
+		return newWaitingError("updated application %q for hub deletion", app.Name)
 	}
 
 	if err := syncApplication(r.argoClient, app, true); err != nil {
 		return err
 	}
 
-	return fmt.Errorf("waiting %d hub child applications to be removed", len(childApps))
+	return newWaitingError("waiting %d hub child applications to be removed", len(childApps))
 }
 
-func (r *PatternReconciler) finalizeObject(instance *api.Pattern) error {
+func (r *PatternReconciler) finalizeObject(instance *api.Pattern, patternsOperatorConfig PatternsOperatorConfig) error {
 	log.Printf("Finalizing pattern object")
 
 	// The object is being deleted and, if prune is enabled, we want to delete all the dependent objects in cascade
 	if strings.EqualFold(instance.Annotations[api.PruneAnnotation], boolTrue) &&
 		(controllerutil.ContainsFinalizer(instance, api.PatternFinalizer) || controllerutil.ContainsFinalizer(instance, metav1.FinalizerOrphanDependents)) {
 		// Prepare the app for cascaded deletion
-		qualifiedInstance, err := r.applyDefaults(instance)
+		qualifiedInstance, err := r.applyDefaults(instance, patternsOperatorConfig)
 		if err != nil {
 			log.Printf("\n\x1b[31;1m\tCannot cleanup the ArgoCD application of an invalid pattern: %s\x1b[0m\n", err.Error())
 			return nil
 		}
 		// Ensure detection has run for the finalize path
-		detectArgoNamespace(r.dynamicClient)
+		provisioner, err := newGitOpsProvisioner(patternsOperatorConfig)
+		if err != nil {
+			log.Printf("Invalid GitOps provisioner, assuming %s: %v", GitOpsProvisionerOpenShiftGitOps, err)
+			provisioner = &openShiftGitOpsProvisioner{config: patternsOperatorConfig}
+		}
+		provisioner.DetectArgoInstance(r)
 		ns := getClusterWideArgoNamespace()
 
-		targetApp := newArgoApplication(qualifiedInstance)
+		// The parameters read from a ConfigMap or Secret must not disappear from the app while it is cleaned up
+		parameterValues, err := resolveExtraParameters(r.fullClient, qualifiedInstance)
+		if err != nil {
+			return err
+		}
+		targetApp := newArgoApplication(qualifiedInstance, parameterValues)
 		_ = controllerutil.SetOwnerReference(qualifiedInstance, targetApp, r.Scheme)
 
 		app, _ := getApplication(r.argoClient, applicationName(qualifiedInstance), ns)
@@ -868,7 +1199,7 @@ func (r *PatternReconciler) finalizeObject(instance *api.Pattern) error {
 				}
 			}
 
-			return fmt.Errorf("initialized deletion phase, requeueing now")
+			return newWaitingError("initialized deletion phase, requeueing now")
 		}
 
 		// Phase 1: Delete child applications from spoke clusters
@@ -881,7 +1212,7 @@ func (r *PatternReconciler) finalizeObject(instance *api.Pattern) error {
 				return err
 			}
 
-			return fmt.Errorf("all child applications are gone, transitioning to %s phase", api.DeleteSpoke)
+			return newWaitingError("all child applications are gone, transitioning to %s phase", api.DeleteSpoke)
 		}
 
 		// Phase 2: Delete app of apps from spoke
@@ -891,7 +1222,7 @@ func (r *PatternReconciler) finalizeObject(instance *api.Pattern) error {
 				return fmt.Errorf("failed to update application %q for spoke app of apps deletion: %v", app.Name, errUpdate)
 			}
 			if changed {
-				return fmt.Errorf("updated application %q for spoke app of apps deletion", app.Name)
+				return newWaitingError("updated application %q for spoke app of apps deletion", app.Name)
 			}
 
 			if err := syncApplication(r.argoClient, app, false); err != nil {
@@ -919,7 +1250,7 @@ func (r *PatternReconciler) finalizeObject(instance *api.Pattern) error {
 				return err
 			}
 
-			return fmt.Errorf("app of apps are gone from spokes, transitioning to %s phase", api.DeleteHubChildApps)
+			return newWaitingError("app of apps are gone from spokes, transitioning to %s phase", api.DeleteHubChildApps)
 		}
 
 		// Phase 3: Delete applications from hub
@@ -932,7 +1263,7 @@ func (r *PatternReconciler) finalizeObject(instance *api.Pattern) error {
 				return err
 			}
 
-			return fmt.Errorf("apps are gone from hub, transitioning to %s phase", api.DeleteHub)
+			return newWaitingError("apps are gone from hub, transitioning to %s phase", api.DeleteHub)
 		}
 		// Phase 4: Delete app of apps from hub
 		if qualifiedInstance.Status.DeletionPhase == api.DeleteHub {
@@ -941,7 +1272,7 @@ func (r *PatternReconciler) finalizeObject(instance *api.Pattern) error {
 				return err
 			}
 			// Clean up the ConsoleLink if we created one
-			if !isLegacyArgoNamespace() {
+			if r.isOpenShift() && !isLegacyArgoNamespace() {
 				err := removeConsoleLink(r.dynamicClient, getClusterWideArgoName())
 				if err != nil {
 					log.Printf("failed to remove the consoleLink: %v", err)
@@ -989,19 +1320,38 @@ func (r *PatternReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	r.giteaOperations = &GiteaOperationsImpl{}
 	r.mgr = mgr
 
-	var ctrlErr error
-	r.ctrl, ctrlErr = ctrl.NewControllerManagedBy(mgr).
-		For(&api.Pattern{}).
+	bldr := ctrl.NewControllerManagedBy(mgr).
+		// Status updates must not trigger a reconcile, or a failing step would be retried right away
+		// instead of after its error backoff
+		For(&api.Pattern{}, builder.WithPredicates(patternChangedPredicate)).
 		// Use Watches instead of Owns: EnqueueRequestForOwner runs RESTMapping on the owner ref; failures
-		// there enqueue nothing and can be hard to spot. We only care about the operator config ConfigMap
-		// and a singleton Pattern in the operator namespace (enforced by webhook), so map directly.
+		// there enqueue nothing and can be hard to spot, so map directly.
+		// The ConfigMaps and Secrets of the whole cluster are watched, only their metadata is cached. The
+		// manager client reads them from the API server, see cmd/main.go
 		Watches(
 			&corev1.ConfigMap{},
-			handler.EnqueueRequestsFromMapFunc(r.enqueuePatternForOperatorConfigMap),
-			builder.WithPredicates(predicate.NewPredicateFuncs(isPatternsOperatorConfigMap)),
+			handler.EnqueueRequestsFromMapFunc(r.enqueuePatternsForConfigMap),
+			builder.OnlyMetadata,
 		).
-		Build(r)
-	return ctrlErr
+		Watches(
+			&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(r.enqueuePatternsForSecret),
+			builder.OnlyMetadata,
+		)
+	// Outbound connections follow the cluster proxy, which only exists on OpenShift
+	openShift, err := IsOpenShiftCluster(r.config)
+	if err != nil {
+		return err
+	}
+	if openShift {
+		bldr = bldr.Watches(
+			&configv1.Proxy{},
+			handler.EnqueueRequestsFromMapFunc(r.enqueueAllPatterns),
+			builder.WithPredicates(predicate.NewPredicateFuncs(isClusterProxy)),
+		)
+	}
+	r.ctrl, err = bldr.Build(r)
+	return err
 }
 
 func isPatternsOperatorConfigMap(obj client.Object) bool {
@@ -1035,6 +1385,40 @@ func (r *PatternReconciler) enqueuePatternForOperatorConfigMap(ctx context.Conte
 	return out
 }
 
+// enqueuePatternsForConfigMap reconciles the patterns affected by a change to patterns-operator-config, or to
+// a ConfigMap their parameters read their value from
+func (r *PatternReconciler) enqueuePatternsForConfigMap(ctx context.Context, obj client.Object) []reconcile.Request {
+	return append(r.enqueuePatternForOperatorConfigMap(ctx, obj), r.enqueuePatternsForParameterSource(ctx, obj, false)...)
+}
+
+// enqueuePatternsForSecret reconciles the patterns whose parameters read their value from the Secret, and
+// propagates rotated git credentials to the copies in the Argo namespaces right away
+func (r *PatternReconciler) enqueuePatternsForSecret(ctx context.Context, obj client.Object) []reconcile.Request {
+	return append(r.enqueuePatternsForParameterSource(ctx, obj, true), r.enqueuePatternsForGitAuthSecret(ctx, obj)...)
+}
+
+// enqueuePatternsForParameterSource enqueues reconcile for the Patterns whose extra parameters
+// read their value from the changed ConfigMap or Secret
+func (r *PatternReconciler) enqueuePatternsForParameterSource(ctx context.Context, obj client.Object, isSecret bool) []reconcile.Request {
+	var list api.PatternList
+	if err := r.List(ctx, &list, client.InNamespace(obj.GetNamespace())); err != nil {
+		ctrl.Log.Error(err, "failed to list Patterns after parameter source change", "namespace", obj.GetNamespace())
+		return nil
+	}
+	var out []reconcile.Request
+	for i := range list.Items {
+		if parameterSourceReferences(&list.Items[i], obj, isSecret) {
+			out = append(out, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Namespace: list.Items[i].Namespace,
+					Name:      list.Items[i].Name,
+				},
+			})
+		}
+	}
+	return out
+}
+
 // startArgoCDWatch dynamically adds a watch on ArgoCD instances so that if
 // the ArgoCD CR is deleted (e.g. during an upgrade that changes the GitOps
 // Subscription), the Pattern controller reconciles immediately and recreates it.
@@ -1086,16 +1470,101 @@ func (r *PatternReconciler) startArgoCDWatch() {
 	r.argoCDWatchStarted = true
 }
 
+// startApplicationWatch dynamically adds a watch on the Argo CD applications that carry the pattern label,
+// so that status.applications follows their sync and health without waiting for the next requeue.
+// Only the metadata of the applications is cached. Events are debounced per pattern, Argo refreshes the
+// status of every application periodically and a large pattern would otherwise reconcile continuously
+func (r *PatternReconciler) startApplicationWatch() {
+	if r.applicationWatchStarted {
+		return
+	}
+
+	if err := checkAPIVersion(r.fullClient, ArgoCDGroup, argoapi.ApplicationSchemaGroupVersionKind.Version); err != nil {
+		return
+	}
+
+	app := &metav1.PartialObjectMetadata{}
+	app.SetGroupVersionKind(argoapi.ApplicationSchemaGroupVersionKind)
+
+	enqueue := func(ctx context.Context, obj *metav1.PartialObjectMetadata, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+		for _, req := range r.patternsForLabel(ctx, obj) {
+			// A pending request is not pushed back, so a pattern reconciles at most once per window
+			q.AddAfter(req, ApplicationEventDebounce)
+		}
+	}
+	err := r.ctrl.Watch(source.Kind(r.mgr.GetCache(), app,
+		handler.TypedFuncs[*metav1.PartialObjectMetadata, reconcile.Request]{
+			CreateFunc: func(ctx context.Context, e event.TypedCreateEvent[*metav1.PartialObjectMetadata], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+				enqueue(ctx, e.Object, q)
+			},
+			UpdateFunc: func(ctx context.Context, e event.TypedUpdateEvent[*metav1.PartialObjectMetadata], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+				enqueue(ctx, e.ObjectNew, q)
+			},
+			DeleteFunc: func(ctx context.Context, e event.TypedDeleteEvent[*metav1.PartialObjectMetadata], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+				enqueue(ctx, e.Object, q)
+			},
+		},
+		predicate.NewTypedPredicateFuncs(func(obj *metav1.PartialObjectMetadata) bool {
+			return obj.GetLabels()[PatternApplicationLabel] != ""
+		}),
+	))
+	if err != nil {
+		ctrl.Log.Error(err, "Failed to start Application watch, will retry on next reconcile")
+		return
+	}
+
+	ctrl.Log.Info("Application watch started successfully")
+	r.applicationWatchStarted = true
+}
+
+// patternsForLabel returns the requests for the patterns named by the pattern label of an object
+func (r *PatternReconciler) patternsForLabel(ctx context.Context, obj client.Object) []reconcile.Request {
+	name := obj.GetLabels()[PatternApplicationLabel]
+	if name == "" {
+		return nil
+	}
+	var patterns api.PatternList
+	if err := r.List(ctx, &patterns); err != nil {
+		return nil
+	}
+	var requests []reconcile.Request
+	for i := range patterns.Items {
+		if patterns.Items[i].Name == name {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: name, Namespace: patterns.Items[i].Namespace},
+			})
+		}
+	}
+	return requests
+}
+
+// patternChangedPredicate lets through the changes of the spec, including deletion which bumps the generation,
+// and of the annotations and labels that suspend or prune a pattern
+var patternChangedPredicate = predicate.Or(
+	predicate.GenerationChangedPredicate{},
+	predicate.AnnotationChangedPredicate{},
+	predicate.LabelChangedPredicate{},
+)
+
 func (r *PatternReconciler) onReconcileErrorWithRequeue(p *api.Pattern, reason string, err error, duration *time.Duration) (reconcile.Result, error) {
 	// err is logged by the reconcileHandler
 	p.Status.LastStep = reason
-	if err != nil {
+	if isWaitingError(err) {
+		p.Status.LastStep = fmt.Sprintf("%s: %s", reason, err.Error())
+		p.Status.LastError = ""
+		p.Status.ConsecutiveFailures = 0
+		log.Printf("\x1b[34;1m\tReconcile step %q waiting: %s\x1b[0m\n", reason, err.Error())
+	} else if err != nil {
 		p.Status.LastError = err.Error()
+		p.Status.ConsecutiveFailures++
 		log.Printf("\x1b[31;1m\tReconcile step %q failed: %s\x1b[0m\n", reason, err.Error())
+		r.recordWarningEvent(p, EventReasonReconcileFailed, "Reconcile step %q failed: %s", reason, err.Error())
 	} else {
 		p.Status.LastError = ""
+		p.Status.ConsecutiveFailures = 0
 		log.Printf("\x1b[34;1m\tReconcile step %q complete\x1b[0m\n", reason)
 	}
+	updateStepConditions(p, reason, err)
 
 	updateErr := r.Client.Status().Update(context.TODO(), p)
 	if updateErr != nil {
@@ -1126,14 +1595,55 @@ func detectArgoNamespace(dynamicClient dynamic.Interface) {
 }
 
 func (r *PatternReconciler) actionPerformed(p *api.Pattern, reason string, err error) (reconcile.Result, error) {
-	if err != nil {
-		delay := time.Minute * 1
+	// Deletion waits on Argo and the spoke clusters, it is polled at the same pace whatever the outcome of a step
+	if !p.DeletionTimestamp.IsZero() {
+		delay := DeletionRequeueTime
+		return r.onReconcileErrorWithRequeue(p, reason, err, &delay)
+	} else if isWaitingError(err) {
+		delay := ReconcileErrorRequeueTime
 		return r.onReconcileErrorWithRequeue(p, reason, err, &delay)
-	} else if !p.DeletionTimestamp.IsZero() {
-		delay := time.Minute * 2
+	} else if err != nil {
+		delay := errorBackoff(p.Status.ConsecutiveFailures + 1)
 		return r.onReconcileErrorWithRequeue(p, reason, err, &delay)
 	}
-	return r.onReconcileErrorWithRequeue(p, reason, err, nil)
+	delay := ReconcileStepRequeueTime
+	return r.onReconcileErrorWithRequeue(p, reason, err, &delay)
+}
+
+// waitingError stops the reconcile loop after a step that made progress or waits for the cluster to settle.
+// It is not a failure: it does not count towards status.consecutiveFailures and its error backoff
+type waitingError struct {
+	msg string
+}
+
+func (e *waitingError) Error() string {
+	return e.msg
+}
+
+func newWaitingError(format string, args ...any) error {
+	return &waitingError{msg: fmt.Sprintf(format, args...)}
+}
+
+func isWaitingError(err error) bool {
+	var waitErr *waitingError
+	return errors.As(err, &waitErr)
+}
+
+// reconcileInterval returns how long to wait before looking for external changes to a healthy pattern
+func reconcileInterval(p *api.Pattern) time.Duration {
+	if p.Spec.ReconcileMinutes > 0 {
+		return time.Duration(p.Spec.ReconcileMinutes) * time.Minute
+	}
+	return ReconcileLoopRequeueTime
+}
+
+// errorBackoff returns the delay before retrying a step that failed the given number of times in a row
+func errorBackoff(failures int) time.Duration {
+	delay := ReconcileErrorRequeueTime
+	for i := 1; i < failures && delay < ReconcileErrorMaxRequeueTime; i++ {
+		delay *= 2
+	}
+	return min(delay, ReconcileErrorMaxRequeueTime)
 }
 
 // updatePatternCRDetails compares the current CR Status.Applications array
@@ -1141,7 +1651,7 @@ func (r *PatternReconciler) actionPerformed(p *api.Pattern, reason string, err e
 // Returns true if the CR was updated else it returns false
 func (r *PatternReconciler) updatePatternCRDetails(input *api.Pattern) (bool, error) {
 	fUpdateCR := false
-	var labelFilter = "validatedpatterns.io/pattern=" + input.Name
+	var labelFilter = PatternApplicationLabel + "=" + input.Name
 
 	// Copy just the applications
 	// Used to compare against input which will be updated with
@@ -1160,43 +1670,18 @@ func (r *PatternReconciler) updatePatternCRDetails(input *api.Pattern) (bool, er
 		return false, err
 	}
 
-	// Reset the array
-	input.Status.Applications = nil
-
-	// Loop through the Pattern Applications and append the details to the Applications array
-	// into input
-	for _, app := range applications.Items { //nolint:gocritic // rangeValCopy: each iteration copies 936 bytes
-		// Add Application information to ApplicationInfo struct
-		var applicationInfo = api.PatternApplicationInfo{
-			Name:            app.Name,
-			Namespace:       app.Namespace,
-			AppHealthStatus: string(app.Status.Health.Status),
-			AppSyncStatus:   string(app.Status.Sync.Status),
-		}
-
-		// Now let's append the Application Information
-		input.Status.Applications = append(input.Status.Applications, applicationInfo)
+	// Loop through the Pattern Applications and collect their details. Health and metrics
+	// are computed from all of them, the status only keeps a bounded list
+	apps := make([]api.PatternApplicationInfo, 0, len(applications.Items))
+	for i := range applications.Items {
+		apps = append(apps, newApplicationInfo(&applications.Items[i]))
 	}
+	setApplicationMetrics(input, apps)
+	input.Status.Applications = boundApplications(apps, api.MaxStatusApplications)
 
-	// Check to see if the Pattern CR has a list of Applications
-	// If it doesn't and we have a list of Applications
-	// Let's update the Pattern CR and set the update flag to true
-	if len(existingApplications) != len(input.Status.Applications) {
+	// Update the Pattern CR when the health or the details of any application changed
+	if updatePatternHealth(input, apps) || !apiequality.Semantic.DeepEqual(existingApplications, input.Status.Applications) {
 		fUpdateCR = true
-	} else {
-		// Compare the array items in the CR for the applications
-		// with the current instance array
-		for _, value := range input.Status.Applications {
-			for _, existingValue := range existingApplications {
-				// Check if AppSyncStatus or AppHealthStatus have been updated
-				if value.Name == existingValue.Name &&
-					(value.AppSyncStatus != existingValue.AppSyncStatus ||
-						value.AppHealthStatus != existingValue.AppHealthStatus) {
-					fUpdateCR = true
-					break // We found a difference break out
-				}
-			}
-		}
 	}
 
 	// Update the Pattern CR if difference was found
@@ -1213,6 +1698,77 @@ func (r *PatternReconciler) updatePatternCRDetails(input *api.Pattern) (bool, er
 	return false, nil
 }
 
+// newApplicationInfo copies the status of an application that matters to the pattern
+func newApplicationInfo(app *argoapi.Application) api.PatternApplicationInfo {
+	info := api.PatternApplicationInfo{
+		Name:                 app.Name,
+		Namespace:            app.Namespace,
+		AppHealthStatus:      string(app.Status.Health.Status),
+		AppHealthMessage:     truncateMessage(app.Status.Health.Message),
+		AppSyncStatus:        string(app.Status.Sync.Status),
+		SyncedRevision:       app.Status.Sync.Revision,
+		DestinationServer:    app.Spec.Destination.Server,
+		DestinationNamespace: app.Spec.Destination.Namespace,
+	}
+	if info.SyncedRevision == "" {
+		info.SyncedRevision = strings.Join(app.Status.Sync.Revisions, ",")
+	}
+	if info.DestinationServer == "" {
+		info.DestinationServer = app.Spec.Destination.Name
+	}
+	if op := app.Status.OperationState; op != nil {
+		info.OperationPhase = string(op.Phase)
+		info.OperationMessage = truncateMessage(op.Message)
+		info.LastSyncedAt = op.FinishedAt
+	}
+	for i := range app.Status.Resources {
+		if app.Status.Resources[i].Status == argoapi.SyncStatusCodeOutOfSync {
+			info.OutOfSyncResources++
+		}
+	}
+	return info
+}
+
+// boundApplications keeps at most limit applications, dropping healthy ones first. The order is preserved
+func boundApplications(apps []api.PatternApplicationInfo, limit int) []api.PatternApplicationInfo {
+	if len(apps) <= limit {
+		return apps
+	}
+	unhealthy := 0
+	for i := range apps {
+		if applicationHealth(&apps[i]) != api.PatternHealthy {
+			unhealthy++
+		}
+	}
+	healthy := max(limit-unhealthy, 0)
+	bounded := make([]api.PatternApplicationInfo, 0, limit)
+	for i := range apps {
+		if len(bounded) == limit {
+			break
+		}
+		if applicationHealth(&apps[i]) == api.PatternHealthy {
+			if healthy == 0 {
+				continue
+			}
+			healthy--
+		}
+		bounded = append(bounded, apps[i])
+	}
+	return bounded
+}
+
+func truncateMessage(message string) string {
+	if len(message) <= api.MaxStatusMessageLength {
+		return message
+	}
+	// Cut on a character boundary
+	cut := api.MaxStatusMessageLength - 3
+	for cut > 0 && !utf8.RuneStart(message[cut]) {
+		cut--
+	}
+	return message[:cut] + "..."
+}
+
 // checkSpokeApplicationsGone checks if all applications are gone from spoke clusters
 // passing appOfApps true will check the app of app instead of child apps
 // The operator runs on the hub cluster and needs to check spoke clusters through ACM Search Service
@@ -1310,6 +1866,7 @@ func (r *PatternReconciler) checkSpokeApplicationsGone(appOfApps bool) (bool, er
 			TLSClientConfig: &tls.Config{
 				InsecureSkipVerify: true, //nolint:gosec
 			},
+			Proxy: proxyForRequest,
 		},
 	}
 
@@ -1371,13 +1928,44 @@ func (r *PatternReconciler) authGitFromSecret(namespace, secret string) (map[str
 	return tokenSecret.Data, nil
 }
 
-func (r *PatternReconciler) copyAuthGitSecret(secretNamespace, secretName, destNamespace, destSecretName string) error {
-	sourceSecret, err := r.authGitFromSecret(secretNamespace, secretName)
+// syncAuthGitSecret keeps the copy of the git auth secret of the pattern in destNamespace up to date
+// and removes it once the pattern no longer references a secret. The copy keeps its well-known name,
+// which the pattern charts rely on, unless several patterns may share the cluster wide Argo namespace
+func (r *PatternReconciler) syncAuthGitSecret(p *api.Pattern, destNamespace string, allowMultiple bool) error {
+	name := gitAuthSecretCopyName(p, allowMultiple)
+	if p.Spec.GitConfig.TokenSecret == "" {
+		if err := r.removeAuthGitSecretCopy(p, destNamespace, name); err != nil {
+			return err
+		}
+	} else if err := r.copyAuthGitSecret(p, destNamespace, name); err != nil {
+		return err
+	}
+	if allowMultiple {
+		return nil
+	}
+	// Drop the copy named after the pattern once the cluster goes back to a single pattern
+	return r.removeAuthGitSecretCopy(p, destNamespace, gitAuthSecretCopyName(p, true))
+}
+
+// gitAuthSecretCopyName names the copy after the pattern when patterns.allowMultiple is set, the cluster wide
+// Argo namespace is then shared by the patterns
+func gitAuthSecretCopyName(p *api.Pattern, allowMultiple bool) string {
+	if !allowMultiple {
+		return GitAuthSecretCopyName
+	}
+	return fmt.Sprintf("%s-%s", GitAuthSecretCopyName, p.Name)
+}
+
+func (r *PatternReconciler) copyAuthGitSecret(p *api.Pattern, destNamespace, name string) error {
+	sourceSecret, err := r.authGitFromSecret(p.Spec.GitConfig.TokenSecretNamespace, p.Spec.GitConfig.TokenSecret)
 	if err != nil {
 		return err
 	}
-	newSecretCopy := newSecret(destSecretName, destNamespace, sourceSecret, map[string]string{"argocd.argoproj.io/secret-type": "repository"})
-	_, err = r.fullClient.CoreV1().Secrets(destNamespace).Get(context.TODO(), destSecretName, metav1.GetOptions{})
+	newSecretCopy := newSecret(name, destNamespace, sourceSecret, map[string]string{
+		"argocd.argoproj.io/secret-type": "repository",
+		PatternApplicationLabel:          p.Name,
+	})
+	currentSecret, err := r.fullClient.CoreV1().Secrets(destNamespace).Get(context.TODO(), name, metav1.GetOptions{})
 	if err != nil {
 		if kerrors.IsNotFound(err) {
 			// Resource does not exist, create it
@@ -1387,15 +1975,60 @@ func (r *PatternReconciler) copyAuthGitSecret(secretNamespace, secretName, destN
 		return err
 	}
 
-	// The destination secret already exists so we upate it and return an error if they were different so the reconcile loop can restart
-	updatedSecret, err := r.fullClient.CoreV1().Secrets(destNamespace).Update(context.TODO(), newSecretCopy, metav1.UpdateOptions{})
-	if err == nil && !compareMaps(newSecretCopy.Data, updatedSecret.Data) {
-		return fmt.Errorf("the secret at %s/%s has been updated", destNamespace, destSecretName)
+	// Only update the copy when the source secret was rotated, or the copy was modified
+	upToDate := compareMaps(currentSecret.Data, newSecretCopy.Data)
+	for key, value := range newSecretCopy.Labels {
+		upToDate = upToDate && currentSecret.Labels[key] == value
+	}
+	if upToDate {
+		return nil
 	}
+	log.Printf("Updating git auth secret %s/%s", destNamespace, name)
+	_, err = r.fullClient.CoreV1().Secrets(destNamespace).Update(context.TODO(), newSecretCopy, metav1.UpdateOptions{})
 	return err
 }
 
-func (r *PatternReconciler) getLocalGit(p *api.Pattern) (string, error) {
+// removeAuthGitSecretCopy deletes the copy of the git auth secret named name in namespace. Copies made by
+// older versions of the operator carry no pattern label and are removed too
+func (r *PatternReconciler) removeAuthGitSecretCopy(p *api.Pattern, namespace, name string) error {
+	secret := &corev1.Secret{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: name}, secret); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if owner := secret.Labels[PatternApplicationLabel]; owner != "" && owner != p.Name {
+		return nil
+	}
+	log.Printf("Removing git auth secret %s/%s", namespace, name)
+	return client.IgnoreNotFound(r.Delete(context.TODO(), secret))
+}
+
+// enqueuePatternsForGitAuthSecret reconciles the patterns that reference a rotated git auth secret,
+// and the pattern a copy belongs to when the copy is modified or deleted
+func (r *PatternReconciler) enqueuePatternsForGitAuthSecret(ctx context.Context, obj client.Object) []reconcile.Request {
+	if strings.HasPrefix(obj.GetName(), GitAuthSecretCopyName) {
+		return r.patternsForLabel(ctx, obj)
+	}
+	var list api.PatternList
+	if err := r.List(ctx, &list); err != nil {
+		ctrl.Log.Error(err, "failed to list Patterns after git auth secret change", "namespace", obj.GetNamespace())
+		return nil
+	}
+	var out []reconcile.Request
+	for i := range list.Items {
+		gc := list.Items[i].Spec.GitConfig
+		if gc.TokenSecret == obj.GetName() && gc.TokenSecretNamespace == obj.GetNamespace() {
+			out = append(out, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Namespace: list.Items[i].Namespace,
+					Name:      list.Items[i].Name,
+				},
+			})
+		}
+	}
+	return out
+}
+
+func (r *PatternReconciler) getLocalGit(p *api.Pattern, patternsOperatorConfig PatternsOperatorConfig) (string, error) {
 	var gitAuthSecret map[string][]byte
 	var err error
 	fmt.Printf("getLocalGit: %s", p.Status.LocalCheckoutPath)
@@ -1403,6 +2036,13 @@ func (r *PatternReconciler) getLocalGit(p *api.Pattern) (string, error) {
 		if gitAuthSecret, err = r.authGitFromSecret(p.Spec.GitConfig.TokenSecretNamespace, p.Spec.GitConfig.TokenSecret); err != nil {
 			return "obtaining git auth info from secret", err
 		}
+		if detectGitAuthType(gitAuthSecret) == GitAuthSsh {
+			gitAuthSecret = r.withSSHKnownHosts(gitAuthSecret, patternsOperatorConfig)
+		}
+	}
+	tlsConfig, err := getGitTLSConfig(r.fullClient, p, gitAuthSecret)
+	if err != nil {
+		return "resolving the CA bundle of the git server", err
 	}
 	// Here we dump all the CAs in kube-root-ca.crt and in openshift-config-managed/trusted-ca-bundle to a file
 	// and then we call git config --global http.sslCAInfo /path/to/your/cacert.pem
@@ -1416,7 +2056,7 @@ func (r *PatternReconciler) getLocalGit(p *api.Pattern) (string, error) {
 
 	gitDir := filepath.Join(p.Status.LocalCheckoutPath, ".git")
 	if _, err := os.Stat(gitDir); os.IsNotExist(err) {
-		err = cloneRepo(r.fullClient, r.gitOperations, p.Spec.GitConfig.TargetRepo, p.Status.LocalCheckoutPath, gitAuthSecret)
+		err = cloneRepo(r.fullClient, r.gitOperations, p.Spec.GitConfig.TargetRepo, p.Status.LocalCheckoutPath, gitAuthSecret, tlsConfig)
 		if err != nil {
 			return "cloning pattern repo", err
 		}
@@ -1431,16 +2071,33 @@ func (r *PatternReconciler) getLocalGit(p *api.Pattern) (string, error) {
 			if err != nil {
 				return "failed to remove locally cloned folder", err
 			}
-			err = cloneRepo(r.fullClient, r.gitOperations, p.Spec.GitConfig.TargetRepo, p.Status.LocalCheckoutPath, gitAuthSecret)
+			err = cloneRepo(r.fullClient, r.gitOperations, p.Spec.GitConfig.TargetRepo, p.Status.LocalCheckoutPath, gitAuthSecret, tlsConfig)
 			if err != nil {
 				return "cloning pattern repo after removal", err
 			}
 		}
 	}
-	if err := checkoutRevision(r.fullClient, r.gitOperations, p.Spec.GitConfig.TargetRepo, p.Status.LocalCheckoutPath,
-		p.Spec.GitConfig.TargetRevision, gitAuthSecret); err != nil {
+	commit, err := checkoutRevision(r.fullClient, r.gitOperations, p.Spec.GitConfig.TargetRepo, p.Status.LocalCheckoutPath,
+		getTargetRevision(p), gitAuthSecret, tlsConfig)
+	if err != nil {
 		return "checkout target revision", err
 	}
+	if commit != nil {
+		signingKeys, err := r.getSigningKeys(p, patternsOperatorConfig)
+		if err != nil {
+			return "reading the signing keys", err
+		}
+		// On failure the status keeps the last verified commit, so Argo stays pinned to it, finalization included
+		if signingKeys != nil {
+			if err := verifyRevisionSignature(p.Status.LocalCheckoutPath, getTargetRevision(p), commit, signingKeys); err != nil {
+				return "verifying the signature of the target revision", err
+			}
+		}
+		if p.Status.Revision == nil || p.Status.Revision.Revision != commit.Hash.String() {
+			p.Status.Revision = newPatternRevision(commit)
+		}
+		p.Status.Revision.Verified = signingKeys != nil
+	}
 
 	if err := r.preValidation(p); err != nil {
 		return "prerequisite validation", err
@@ -1448,10 +2105,11 @@ func (r *PatternReconciler) getLocalGit(p *api.Pattern) (string, error) {
 	return "", nil
 }
 
-func DropLocalGitPaths() error {
+// DropLocalGitPaths removes the local checkouts of a single pattern
+func DropLocalGitPaths(namespace, name string) error {
 	// If there is a completely new local folder, let's remove the old one
 	// User changed the target repo
-	err := os.RemoveAll(filepath.Join(os.TempDir(), VPTmpFolder))
+	err := os.RemoveAll(getPatternGitPath(namespace, name))
 	if err != nil {
 		return err
 	}
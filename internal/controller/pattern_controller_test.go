@@ -18,9 +18,15 @@ package controllers
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
 
+	argoapi "github.com/argoproj/argo-cd/v3/pkg/apis/application/v1alpha1"
+	argoclient "github.com/argoproj/argo-cd/v3/pkg/client/clientset/versioned/fake"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-logr/logr"
 	api "github.com/hybrid-cloud-patterns/patterns-operator/api/v1alpha1"
@@ -28,20 +34,31 @@ import (
 	. "github.com/onsi/gomega"
 	v1 "github.com/openshift/api/config/v1"
 	operatorv1 "github.com/openshift/api/operator/v1"
+	routev1 "github.com/openshift/api/route/v1"
 	configclient "github.com/openshift/client-go/config/clientset/versioned/fake"
 	operatorclient "github.com/openshift/client-go/operator/clientset/versioned/fake"
+	routefake "github.com/openshift/client-go/route/clientset/versioned/fake"
 	olmclient "github.com/operator-framework/operator-lifecycle-manager/pkg/api/client/clientset/versioned/fake"
 	gomock "go.uber.org/mock/gomock"
 
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes"
 	kubeclient "k8s.io/client-go/kubernetes/fake"
 
 	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 const (
@@ -116,12 +133,14 @@ func newFakeReconciler(initObjects ...runtime.Object) *PatternReconciler {
 		Spec:       operatorv1.OpenShiftControllerManagerSpec{},
 		Status:     operatorv1.OpenShiftControllerManagerStatus{OperatorStatus: operatorv1.OperatorStatus{Version: "4.10.3"}}}
 	ingress := &v1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}, Spec: v1.IngressSpec{Domain: "hello.world"}}
+	configClient := configclient.NewSimpleClientset(clusterVersion, clusterInfra, ingress)
 	return &PatternReconciler{
 		Scheme:          scheme.Scheme,
 		Client:          fakeClient,
 		olmClient:       olmclient.NewSimpleClientset(),
 		fullClient:      kubeclient.NewSimpleClientset(),
-		configClient:    configclient.NewSimpleClientset(clusterVersion, clusterInfra, ingress),
+		configClient:    configClient,
+		platform:        &openShiftPlatform{configClient: configClient},
 		operatorClient:  operatorclient.NewSimpleClientset(osControlManager).OperatorV1(),
 		AnalyticsClient: AnalyticsInit(true, logr.New(log.NullLogSink{})),
 		gitOperations:   mockGitOps,
@@ -237,6 +256,20 @@ var _ = Describe("pattern controller - preValidation", func() {
 		Expect(err).To(HaveOccurred())
 	})
 
+	It("should fail when spec.values is not an object", func() {
+		p := &api.Pattern{
+			Spec: api.PatternSpec{
+				GitConfig: api.GitConfig{
+					TargetRepo: "https://github.com/test/repo",
+				},
+				Values: &runtime.RawExtension{Raw: []byte(`"global.foo=bar"`)},
+			},
+		}
+		err := reconciler.preValidation(p)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("could not parse spec.values as a map"))
+	})
+
 	It("should pass when origin repo is empty but target repo is valid", func() {
 		p := &api.Pattern{
 			Spec: api.PatternSpec{
@@ -382,51 +415,51 @@ var _ = Describe("pattern controller - condition utilities", func() {
 	BeforeEach(func() {
 		pattern = &api.Pattern{
 			Status: api.PatternStatus{
-				Conditions: []api.PatternCondition{},
+				Conditions: []metav1.Condition{},
 			},
 		}
 	})
 
 	Context("setPatternCondition", func() {
 		It("should add a new condition when none exists", func() {
-			setPatternCondition(pattern, api.Missing, corev1.ConditionTrue, "values file missing")
+			setPatternCondition(pattern, api.Missing, metav1.ConditionTrue, api.ReasonValuesFileMissing, "values file missing")
 
 			Expect(pattern.Status.Conditions).To(HaveLen(1))
 			condition := pattern.Status.Conditions[0]
-			Expect(condition.Type).To(Equal(api.Missing))
-			Expect(condition.Status).To(Equal(corev1.ConditionTrue))
+			Expect(condition.Type).To(Equal(string(api.Missing)))
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
 			Expect(condition.Message).To(Equal("values file missing"))
-			Expect(condition.LastUpdateTime).ToNot(BeZero())
+			Expect(condition.Reason).To(Equal(api.ReasonValuesFileMissing))
 			Expect(condition.LastTransitionTime).ToNot(BeZero())
 		})
 
 		It("should update an existing condition with new status", func() {
 			// Add initial condition
-			setPatternCondition(pattern, api.Missing, corev1.ConditionFalse, "initial message")
+			setPatternCondition(pattern, api.Missing, metav1.ConditionFalse, api.ReasonReconciled, "initial message")
 			initialTransitionTime := pattern.Status.Conditions[0].LastTransitionTime
 
 			// Update with new status
-			setPatternCondition(pattern, api.Missing, corev1.ConditionTrue, "updated message")
+			setPatternCondition(pattern, api.Missing, metav1.ConditionTrue, api.ReasonValuesFileMissing, "updated message")
 
 			Expect(pattern.Status.Conditions).To(HaveLen(1))
 			condition := pattern.Status.Conditions[0]
-			Expect(condition.Type).To(Equal(api.Missing))
-			Expect(condition.Status).To(Equal(corev1.ConditionTrue))
+			Expect(condition.Type).To(Equal(string(api.Missing)))
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
 			Expect(condition.Message).To(Equal("updated message"))
 			Expect(condition.LastTransitionTime).ToNot(Equal(initialTransitionTime))
 		})
 
 		It("should preserve transition time when status doesn't change", func() {
 			// Add initial condition
-			setPatternCondition(pattern, api.Missing, corev1.ConditionTrue, "initial message")
+			setPatternCondition(pattern, api.Missing, metav1.ConditionTrue, api.ReasonValuesFileMissing, "initial message")
 			initialTransitionTime := pattern.Status.Conditions[0].LastTransitionTime
 
 			// Update with same status but different message
-			setPatternCondition(pattern, api.Missing, corev1.ConditionTrue, "updated message")
+			setPatternCondition(pattern, api.Missing, metav1.ConditionTrue, api.ReasonValuesFileMissing, "updated message")
 
 			Expect(pattern.Status.Conditions).To(HaveLen(1))
 			condition := pattern.Status.Conditions[0]
-			Expect(condition.Status).To(Equal(corev1.ConditionTrue))
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
 			Expect(condition.Message).To(Equal("updated message"))
 			Expect(condition.LastTransitionTime).To(Equal(initialTransitionTime))
 		})
@@ -435,25 +468,598 @@ var _ = Describe("pattern controller - condition utilities", func() {
 	Context("removePatternCondition", func() {
 		It("should remove an existing condition", func() {
 			// Add multiple conditions
-			setPatternCondition(pattern, api.Missing, corev1.ConditionTrue, "missing message")
-			setPatternCondition(pattern, api.Progressing, corev1.ConditionTrue, "progressing message")
+			setPatternCondition(pattern, api.Missing, metav1.ConditionTrue, api.ReasonValuesFileMissing, "missing message")
+			setPatternCondition(pattern, api.Progressing, metav1.ConditionTrue, api.ReasonValuesFileMissing, "progressing message")
 			Expect(pattern.Status.Conditions).To(HaveLen(2))
 
 			// Remove one condition
 			removePatternCondition(pattern, api.Missing)
 
 			Expect(pattern.Status.Conditions).To(HaveLen(1))
-			Expect(pattern.Status.Conditions[0].Type).To(Equal(api.Progressing))
+			Expect(pattern.Status.Conditions[0].Type).To(Equal(string(api.Progressing)))
 		})
 
 		It("should handle removing non-existent condition gracefully", func() {
-			setPatternCondition(pattern, api.Progressing, corev1.ConditionTrue, "progressing message")
+			setPatternCondition(pattern, api.Progressing, metav1.ConditionTrue, api.ReasonValuesFileMissing, "progressing message")
 
 			removePatternCondition(pattern, api.Missing) // This doesn't exist
 
 			Expect(pattern.Status.Conditions).To(HaveLen(1))
-			Expect(pattern.Status.Conditions[0].Type).To(Equal(api.Progressing))
+			Expect(pattern.Status.Conditions[0].Type).To(Equal(string(api.Progressing)))
+		})
+	})
+})
+
+var _ = Describe("pattern controller - updateGitSyncConditions", func() {
+	var pattern *api.Pattern
+
+	BeforeEach(func() {
+		pattern = buildPatternManifest()
+		pattern.Spec.GitConfig.TargetRevision = "main"
+	})
+
+	It("should not set any condition when the revision could not be resolved", func() {
+		Expect(updateGitSyncConditions(pattern, "", "abc123")).To(BeFalse())
+		Expect(pattern.Status.Conditions).To(BeEmpty())
+	})
+
+	It("should set GitInSync when Argo synced the resolved commit", func() {
+		Expect(updateGitSyncConditions(pattern, "abc123", "abc123")).To(BeTrue())
+		Expect(pattern.Status.Conditions).To(HaveLen(1))
+		Expect(pattern.Status.Conditions[0].Type).To(Equal(string(api.GitInSync)))
+		Expect(pattern.Status.Conditions[0].Status).To(Equal(metav1.ConditionTrue))
+		Expect(pattern.Status.Conditions[0].Reason).To(Equal(api.ReasonCommitSynced))
+		Expect(pattern.Status.Conditions[0].Message).To(ContainSubstring("abc123"))
+	})
+
+	It("should set GitOutOfSync with both commits when they differ", func() {
+		Expect(updateGitSyncConditions(pattern, "def456", "abc123")).To(BeTrue())
+		Expect(pattern.Status.Conditions).To(HaveLen(1))
+		Expect(pattern.Status.Conditions[0].Type).To(Equal(string(api.GitOutOfSync)))
+		Expect(pattern.Status.Conditions[0].Message).To(ContainSubstring("abc123"))
+		Expect(pattern.Status.Conditions[0].Message).To(ContainSubstring("def456"))
+	})
+
+	It("should set GitOutOfSync when Argo has not synced yet", func() {
+		Expect(updateGitSyncConditions(pattern, "def456", "")).To(BeTrue())
+		Expect(pattern.Status.Conditions[0].Type).To(Equal(string(api.GitOutOfSync)))
+		Expect(pattern.Status.Conditions[0].Message).To(ContainSubstring("not synced"))
+	})
+
+	It("should replace GitOutOfSync with GitInSync once Argo catches up", func() {
+		updateGitSyncConditions(pattern, "def456", "abc123")
+		Expect(updateGitSyncConditions(pattern, "def456", "def456")).To(BeTrue())
+		Expect(pattern.Status.Conditions).To(HaveLen(1))
+		Expect(pattern.Status.Conditions[0].Type).To(Equal(string(api.GitInSync)))
+	})
+
+	It("should report no change when nothing changed", func() {
+		updateGitSyncConditions(pattern, "abc123", "abc123")
+		Expect(updateGitSyncConditions(pattern, "abc123", "abc123")).To(BeFalse())
+	})
+})
+
+var _ = Describe("pattern controller - recordRevisionHistory", func() {
+	var pattern *api.Pattern
+
+	BeforeEach(func() {
+		pattern = buildPatternManifest()
+	})
+
+	It("should not record anything when no revision was resolved", func() {
+		Expect(recordRevisionHistory(pattern)).To(BeFalse())
+		Expect(pattern.Status.RevisionHistory).To(BeEmpty())
+	})
+
+	It("should record the first deployed revision", func() {
+		pattern.Status.Revision = &api.PatternRevision{Revision: "abc123", Author: "Jane <jane@example.com>", Message: "First"}
+		Expect(recordRevisionHistory(pattern)).To(BeTrue())
+		Expect(pattern.Status.RevisionHistory).To(HaveLen(1))
+		Expect(pattern.Status.RevisionHistory[0]).To(Equal(*pattern.Status.Revision))
+	})
+
+	It("should not record the same revision twice", func() {
+		pattern.Status.Revision = &api.PatternRevision{Revision: "abc123"}
+		recordRevisionHistory(pattern)
+		Expect(recordRevisionHistory(pattern)).To(BeFalse())
+		Expect(pattern.Status.RevisionHistory).To(HaveLen(1))
+	})
+
+	It("should keep the most recent revision first", func() {
+		pattern.Status.Revision = &api.PatternRevision{Revision: "abc123"}
+		recordRevisionHistory(pattern)
+		pattern.Status.Revision = &api.PatternRevision{Revision: "def456"}
+		Expect(recordRevisionHistory(pattern)).To(BeTrue())
+		Expect(pattern.Status.RevisionHistory).To(HaveLen(2))
+		Expect(pattern.Status.RevisionHistory[0].Revision).To(Equal("def456"))
+		Expect(pattern.Status.RevisionHistory[1].Revision).To(Equal("abc123"))
+	})
+
+	It("should drop the oldest revisions beyond the limit", func() {
+		for i := 0; i < RevisionHistoryLimit+3; i++ {
+			pattern.Status.Revision = &api.PatternRevision{Revision: fmt.Sprintf("rev%d", i)}
+			recordRevisionHistory(pattern)
+		}
+		Expect(pattern.Status.RevisionHistory).To(HaveLen(RevisionHistoryLimit))
+		Expect(pattern.Status.RevisionHistory[0].Revision).To(Equal(fmt.Sprintf("rev%d", RevisionHistoryLimit+2)))
+		Expect(pattern.Status.RevisionHistory[RevisionHistoryLimit-1].Revision).To(Equal("rev3"))
+	})
+})
+
+var _ = Describe("pattern controller - rollback revision", func() {
+	const rollback = "d0f3fb283cfb17189cba89aa5ff57fd8dcb2a7fd"
+	var pattern *api.Pattern
+
+	BeforeEach(func() {
+		pattern = buildPatternManifest()
+		pattern.Spec.GitConfig.TargetRevision = "main"
+		pattern.Status.RevisionHistory = []api.PatternRevision{{Revision: "abc123"}, {Revision: rollback}}
+	})
+
+	It("should accept an empty rollback revision", func() {
+		Expect(validateRollbackRevision(pattern)).To(Succeed())
+	})
+
+	It("should accept a rollback revision from the history", func() {
+		pattern.Spec.GitConfig.RollbackRevision = rollback
+		Expect(validateRollbackRevision(pattern)).To(Succeed())
+	})
+
+	It("should reject a rollback revision the pattern was never deployed at", func() {
+		pattern.Spec.GitConfig.RollbackRevision = "0000000000000000000000000000000000000000"
+		err := validateRollbackRevision(pattern)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("not in the revision history"))
+	})
+
+	It("should set RolledBack while the pin is active and remove it once cleared", func() {
+		pattern.Spec.GitConfig.RollbackRevision = rollback
+		Expect(updateRolledBackCondition(pattern)).To(BeTrue())
+		Expect(pattern.Status.Conditions).To(HaveLen(1))
+		Expect(pattern.Status.Conditions[0].Type).To(Equal(string(api.RolledBack)))
+		Expect(pattern.Status.Conditions[0].Reason).To(Equal(api.ReasonRollbackPinned))
+		Expect(pattern.Status.Conditions[0].Message).To(ContainSubstring(rollback))
+		Expect(updateRolledBackCondition(pattern)).To(BeFalse())
+
+		pattern.Spec.GitConfig.RollbackRevision = ""
+		Expect(updateRolledBackCondition(pattern)).To(BeTrue())
+		Expect(pattern.Status.Conditions).To(BeEmpty())
+		Expect(updateRolledBackCondition(pattern)).To(BeFalse())
+	})
+})
+
+var _ = Describe("pattern controller - suspend", func() {
+	var pattern *api.Pattern
+
+	BeforeEach(func() {
+		pattern = buildPatternManifest()
+	})
+
+	It("should be suspended by spec.suspend or the suspend annotation", func() {
+		Expect(isPatternSuspended(pattern)).To(BeFalse())
+		pattern.Spec.Suspend = true
+		Expect(isPatternSuspended(pattern)).To(BeTrue())
+		pattern.Spec.Suspend = false
+		pattern.Annotations = map[string]string{api.SuspendAnnotation: "True"}
+		Expect(isPatternSuspended(pattern)).To(BeTrue())
+		pattern.Annotations[api.SuspendAnnotation] = "false"
+		Expect(isPatternSuspended(pattern)).To(BeFalse())
+	})
+
+	It("should set Suspended while suspended and remove it once resumed", func() {
+		Expect(updateSuspendedCondition(pattern)).To(BeFalse())
+		pattern.Spec.Suspend = true
+		Expect(updateSuspendedCondition(pattern)).To(BeTrue())
+		_, condition := getPatternConditionByType(pattern.Status.Conditions, api.Suspended)
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		_, ready := getPatternConditionByType(pattern.Status.Conditions, api.Ready)
+		Expect(ready).ToNot(BeNil())
+		Expect(ready.Status).To(Equal(metav1.ConditionUnknown))
+		Expect(ready.Reason).To(Equal(api.ReasonSuspended))
+		Expect(updateSuspendedCondition(pattern)).To(BeFalse())
+
+		pattern.Spec.Suspend = false
+		Expect(updateSuspendedCondition(pattern)).To(BeTrue())
+		_, condition = getPatternConditionByType(pattern.Status.Conditions, api.Suspended)
+		Expect(condition).To(BeNil())
+	})
+
+	It("should only report the application status while suspended, writing nothing but the pattern status", func() {
+		pattern.Spec.Suspend = true
+		app := &argoapi.Application{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "hello-world",
+				Namespace: "openshift-gitops",
+				Labels:    map[string]string{"validatedpatterns.io/pattern": foo},
+			},
+			Status: argoapi.ApplicationStatus{
+				Health: argoapi.AppHealthStatus{Status: "Healthy"},
+				Sync:   argoapi.SyncStatus{Status: "Synced"},
+			},
+		}
+		reconciler := newFakeReconciler()
+		// Only the status of the pattern may be written
+		var writes []string
+		recordWrite := func(verb string, obj client.Object) {
+			writes = append(writes, fmt.Sprintf("%s %T %s", verb, obj, obj.GetName()))
+		}
+		reconciler.Client = fake.NewClientBuilder().WithScheme(scheme.Scheme).
+			WithObjects(pattern).WithStatusSubresource(&api.Pattern{}).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+					recordWrite("create", obj)
+					return c.Create(ctx, obj, opts...)
+				},
+				Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+					recordWrite("update", obj)
+					return c.Update(ctx, obj, opts...)
+				},
+				Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+					recordWrite("patch", obj)
+					return c.Patch(ctx, obj, patch, opts...)
+				},
+				Delete: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+					recordWrite("delete", obj)
+					return c.Delete(ctx, obj, opts...)
+				},
+			}).Build()
+		recorder := record.NewFakeRecorder(10)
+		reconciler.Recorder = recorder
+		argoClient := argoclient.NewSimpleClientset(app)
+		reconciler.argoClient = argoClient
+		fullClient := kubeclient.NewSimpleClientset()
+		reconciler.fullClient = fullClient
+
+		result, err := reconciler.Reconcile(context.Background(), reconcile.Request{NamespacedName: patternNamespaced})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(ReconcileLoopRequeueTime))
+		Expect(writes).To(BeEmpty())
+		Expect(fullClient.Actions()).To(BeEmpty())
+
+		stored := &api.Pattern{}
+		Expect(reconciler.Client.Get(context.Background(), patternNamespaced, stored)).To(Succeed())
+		Expect(stored.Status.LastStep).To(Equal("reconciliation suspended"))
+		Expect(stored.Status.Applications).To(HaveLen(1))
+		Expect(stored.Status.Applications[0].AppHealthStatus).To(Equal("Healthy"))
+		_, condition := getPatternConditionByType(stored.Status.Conditions, api.Suspended)
+		Expect(condition).ToNot(BeNil())
+
+		for _, action := range argoClient.Actions() {
+			Expect(action.GetVerb()).To(Equal("list"))
+		}
+		Expect(recorder.Events).To(Receive(Equal("Normal Suspended Reconciliation suspended")))
+	})
+})
+
+var _ = Describe("pattern controller - requeue intervals", func() {
+	It("should use the default interval unless the pattern sets one", func() {
+		pattern := buildPatternManifest()
+		Expect(reconcileInterval(pattern)).To(Equal(ReconcileLoopRequeueTime))
+		pattern.Spec.ReconcileMinutes = 10
+		Expect(reconcileInterval(pattern)).To(Equal(10 * time.Minute))
+	})
+
+	It("should double the error delay up to the cap", func() {
+		Expect(errorBackoff(0)).To(Equal(ReconcileErrorRequeueTime))
+		Expect(errorBackoff(1)).To(Equal(ReconcileErrorRequeueTime))
+		Expect(errorBackoff(2)).To(Equal(2 * ReconcileErrorRequeueTime))
+		Expect(errorBackoff(4)).To(Equal(8 * ReconcileErrorRequeueTime))
+		Expect(errorBackoff(6)).To(Equal(ReconcileErrorMaxRequeueTime))
+		Expect(errorBackoff(1000)).To(Equal(ReconcileErrorMaxRequeueTime))
+	})
+
+	It("should back off on consecutive failures and reset on success", func() {
+		pattern := buildPatternManifest()
+		reconciler := newFakeReconciler()
+		reconciler.Client = fake.NewClientBuilder().WithScheme(scheme.Scheme).
+			WithObjects(pattern).WithStatusSubresource(&api.Pattern{}).Build()
+		recorder := record.NewFakeRecorder(10)
+		reconciler.Recorder = recorder
+		current := &api.Pattern{}
+		Expect(reconciler.Client.Get(context.Background(), patternNamespaced, current)).To(Succeed())
+
+		for _, expected := range []time.Duration{1 * time.Minute, 2 * time.Minute, 4 * time.Minute} {
+			result, err := reconciler.actionPerformed(current, "cloning pattern repo", fmt.Errorf("connection refused"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(expected))
+		}
+		Expect(current.Status.ConsecutiveFailures).To(Equal(3))
+		Expect(recorder.Events).To(Receive(Equal(`Warning ReconcileFailed Reconcile step "cloning pattern repo" failed: connection refused`)))
+
+		result, err := reconciler.actionPerformed(current, "created ArgoCD namespace", nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(ReconcileStepRequeueTime))
+		Expect(current.Status.ConsecutiveFailures).To(BeZero())
+		result, err = reconciler.actionPerformed(current, "cloning pattern repo", fmt.Errorf("connection refused"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(ReconcileErrorRequeueTime))
+	})
+
+	It("should not retry a failed step before its backoff on the status update", func() {
+		pattern := buildPatternManifest()
+		pattern.Generation = 1
+		reconciler := newFakeReconciler()
+		reconciler.Client = fake.NewClientBuilder().WithScheme(scheme.Scheme).
+			WithObjects(pattern).WithStatusSubresource(&api.Pattern{}).Build()
+		current := &api.Pattern{}
+		Expect(reconciler.Client.Get(context.Background(), patternNamespaced, current)).To(Succeed())
+		old := current.DeepCopy()
+
+		result, err := reconciler.actionPerformed(current, "cloning pattern repo", fmt.Errorf("connection refused"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(ReconcileErrorRequeueTime))
+		updated := &api.Pattern{}
+		Expect(reconciler.Client.Get(context.Background(), patternNamespaced, updated)).To(Succeed())
+		Expect(updated.Status.ConsecutiveFailures).To(Equal(1))
+		Expect(patternChangedPredicate.Update(event.UpdateEvent{ObjectOld: old, ObjectNew: updated})).To(BeFalse())
+
+		suspended := updated.DeepCopy()
+		suspended.Annotations = map[string]string{api.SuspendAnnotation: "true"}
+		Expect(patternChangedPredicate.Update(event.UpdateEvent{ObjectOld: updated, ObjectNew: suspended})).To(BeTrue())
+		edited := updated.DeepCopy()
+		edited.Generation++
+		Expect(patternChangedPredicate.Update(event.UpdateEvent{ObjectOld: updated, ObjectNew: edited})).To(BeTrue())
+	})
+
+	It("should not back off while waiting for the cluster", func() {
+		pattern := buildPatternManifest()
+		reconciler := newFakeReconciler()
+		reconciler.Client = fake.NewClientBuilder().WithScheme(scheme.Scheme).
+			WithObjects(pattern).WithStatusSubresource(&api.Pattern{}).Build()
+		current := &api.Pattern{}
+		Expect(reconciler.Client.Get(context.Background(), patternNamespaced, current)).To(Succeed())
+		current.Status.ConsecutiveFailures = 4
+
+		for range 3 {
+			result, err := reconciler.actionPerformed(current, "error created gitea instance", newWaitingError("waiting for giteanamespace creation"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(ReconcileErrorRequeueTime))
+		}
+		Expect(current.Status.ConsecutiveFailures).To(BeZero())
+		Expect(current.Status.LastError).To(BeEmpty())
+		Expect(current.Status.LastStep).To(Equal("error created gitea instance: waiting for giteanamespace creation"))
+		Expect(isConditionTrue(current, api.Ready)).To(BeFalse())
+		_, ready := getPatternConditionByType(current.Status.Conditions, api.Ready)
+		Expect(ready.Reason).To(Equal(api.ReasonProgressing))
+	})
+
+	It("should poll a pattern being deleted at the deletion pace whatever the outcome", func() {
+		pattern := buildPatternManifest()
+		now := metav1.Now()
+		pattern.DeletionTimestamp = &now
+		pattern.Finalizers = []string{api.PatternFinalizer}
+		reconciler := newFakeReconciler()
+		reconciler.Client = fake.NewClientBuilder().WithScheme(scheme.Scheme).
+			WithObjects(pattern).WithStatusSubresource(&api.Pattern{}).Build()
+		current := &api.Pattern{}
+		Expect(reconciler.Client.Get(context.Background(), patternNamespaced, current)).To(Succeed())
+
+		for _, stepErr := range []error{
+			newWaitingError("waiting 3 hub child applications to be removed"),
+			fmt.Errorf("connection refused"),
+			fmt.Errorf("connection refused"),
+			nil,
+		} {
+			result, err := reconciler.actionPerformed(current, "finalize", stepErr)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(DeletionRequeueTime))
+		}
+	})
+})
+
+// fakeGiteaOperations reports every repository as migrated
+type fakeGiteaOperations struct{}
+
+func (g *fakeGiteaOperations) MigrateGiteaRepo(_ kubernetes.Interface, _, _, _, _ string) (success bool, repositoryURL string, err error) {
+	return true, "", nil
+}
+
+var _ = Describe("pattern controller - events", func() {
+	It("should record deletion phase transitions", func() {
+		pattern := buildPatternManifest()
+		reconciler := newFakeReconciler()
+		reconciler.Client = fake.NewClientBuilder().WithScheme(scheme.Scheme).
+			WithObjects(pattern).WithStatusSubresource(&api.Pattern{}).Build()
+		recorder := record.NewFakeRecorder(10)
+		reconciler.Recorder = recorder
+		current := &api.Pattern{}
+		Expect(reconciler.Client.Get(context.Background(), patternNamespaced, current)).To(Succeed())
+
+		Expect(reconciler.updateDeletionPhase(current, api.DeleteSpokeChildApps)).To(Succeed())
+		Expect(recorder.Events).To(Receive(Equal(fmt.Sprintf(`Normal DeletionPhaseChanged Deletion phase changed from "" to %q`, api.DeleteSpokeChildApps))))
+		Expect(current.Status.DeletionPhase).To(Equal(api.DeleteSpokeChildApps))
+	})
+
+	It("should not record warnings for the progress of a deletion", func() {
+		pattern := buildPatternManifest()
+		now := metav1.Now()
+		pattern.DeletionTimestamp = &now
+		reconciler := newFakeReconciler()
+		reconciler.Client = fake.NewClientBuilder().WithScheme(scheme.Scheme).
+			WithObjects(pattern).WithStatusSubresource(&api.Pattern{}).Build()
+		recorder := record.NewFakeRecorder(10)
+		reconciler.Recorder = recorder
+		current := &api.Pattern{}
+		Expect(reconciler.Client.Get(context.Background(), patternNamespaced, current)).To(Succeed())
+
+		Expect(reconciler.updateDeletionPhase(current, api.DeleteHubChildApps)).To(Succeed())
+		_, err := reconciler.actionPerformed(current, "finalize", newWaitingError("waiting 2 hub child applications to be removed"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(recorder.Events).To(Receive(HavePrefix("Normal DeletionPhaseChanged")))
+		Expect(recorder.Events).ToNot(Receive())
+	})
+
+	It("should keep the app of apps on the verified commit while finalizing", func() {
+		const verified = "0123456789abcdef0123456789abcdef01234567"
+		pattern := buildPatternManifest()
+		now := metav1.Now()
+		pattern.DeletionTimestamp = &now
+		pattern.Annotations = map[string]string{api.PruneAnnotation: boolTrue}
+		pattern.Spec.GitConfig.SigningKeysFrom = &api.PatternParameterSource{ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "signing-keys"}, Key: "keys",
+		}}
+		pattern.Status.DeletionPhase = api.DeleteHubChildApps
+		pattern.Status.Revision = &api.PatternRevision{Revision: verified, Verified: true}
+		reconciler := newFakeReconciler()
+		reconciler.dynamicClient = dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+		qualified, err := reconciler.applyDefaults(pattern, nil)
+		Expect(err).ToNot(HaveOccurred())
+		deployed := newArgoApplication(qualified, nil)
+		deployed.Namespace = ApplicationNamespace
+		child := &argoapi.Application{ObjectMeta: metav1.ObjectMeta{Name: "child", Namespace: ApplicationNamespace, Annotations: map[string]string{
+			"argocd.argoproj.io/tracking-id": deployed.Name + ":argoproj.io/Application:" + ApplicationNamespace + "/child",
+		}}}
+		reconciler.argoClient = argoclient.NewSimpleClientset(deployed, child)
+
+		err = reconciler.finalizeObject(pattern, nil)
+		Expect(err).To(MatchError(ContainSubstring("waiting 1 hub child applications to be removed")))
+		app, err := reconciler.argoClient.ArgoprojV1alpha1().Applications(ApplicationNamespace).Get(context.Background(), deployed.Name, metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(app.Spec.Sources[0].TargetRevision).To(Equal(verified))
+	})
+
+	It("should leave the gitea application of another pattern alone", func() {
+		pattern := buildPatternManifest()
+		pattern.Spec.GitConfig.OriginRepo = "https://github.com/validatedpatterns/multicloud-gitops"
+		pattern.Spec.GitOpsConfig = &api.GitOpsConfig{}
+		reconciler := newFakeReconciler()
+		reconciler.Client = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(pattern).Build()
+		reconciler.fullClient = kubeclient.NewSimpleClientset(newSecret(GiteaAdminSecretName, GiteaNamespace, map[string][]byte{
+			secretFieldUsername: []byte(GiteaAdminUser), secretFieldPassword: []byte("secret"),
+		}, nil))
+		platform := buildPatternManifest()
+		platform.Name = "platform"
+		platform.Spec.GitOpsConfig = &api.GitOpsConfig{}
+		giteaApp := newArgoGiteaApplication(platform, nil)
+		reconciler.argoClient = argoclient.NewSimpleClientset(giteaApp)
+
+		// Stops at the gitea namespace, which is only created once the application synced
+		Expect(reconciler.createGiteaInstance(pattern, nil)).To(MatchError(ContainSubstring("waiting for giteanamespace creation")))
+		app, err := reconciler.argoClient.ArgoprojV1alpha1().Applications(giteaApp.Namespace).Get(context.Background(), GiteaApplicationName, metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(app.Labels).To(HaveKeyWithValue(PatternApplicationLabel, platform.Name))
+		Expect(app.ResourceVersion).To(Equal(giteaApp.ResourceVersion))
+	})
+
+	It("should only record the gitea migration when the target repo changes", func() {
+		pattern := buildPatternManifest()
+		pattern.Spec.GitConfig.OriginRepo = "https://github.com/validatedpatterns/multicloud-gitops"
+		pattern.Spec.GitOpsConfig = &api.GitOpsConfig{}
+		reconciler := newFakeReconciler()
+		reconciler.Client = fake.NewClientBuilder().WithScheme(scheme.Scheme).
+			WithObjects(pattern, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: GiteaNamespace}}).
+			WithStatusSubresource(&api.Pattern{}).Build()
+		recorder := record.NewFakeRecorder(10)
+		reconciler.Recorder = recorder
+		reconciler.fullClient = kubeclient.NewSimpleClientset(newSecret(GiteaAdminSecretName, GiteaNamespace, map[string][]byte{
+			secretFieldUsername: []byte(GiteaAdminUser), secretFieldPassword: []byte("secret"),
+		}, nil))
+		reconciler.routeClient = routefake.NewSimpleClientset(&routev1.Route{
+			ObjectMeta: metav1.ObjectMeta{Name: GiteaRouteName, Namespace: GiteaNamespace},
+			Status:     routev1.RouteStatus{Ingress: []routev1.RouteIngress{{Host: "gitea.apps.example.com"}}},
 		})
+		reconciler.giteaOperations = &fakeGiteaOperations{}
+		current := &api.Pattern{}
+		Expect(reconciler.Client.Get(context.Background(), patternNamespaced, current)).To(Succeed())
+		giteaApp := newArgoGiteaApplication(current, nil)
+		_ = controllerutil.SetOwnerReference(current, giteaApp, reconciler.Scheme)
+		reconciler.argoClient = argoclient.NewSimpleClientset(giteaApp)
+
+		Expect(reconciler.createGiteaInstance(current, nil)).To(Succeed())
+		giteaRepoURL := "https://gitea.apps.example.com/" + GiteaAdminUser + "/" + "multicloud-gitops"
+		Expect(current.Spec.GitConfig.TargetRepo).To(Equal(giteaRepoURL))
+		Expect(recorder.Events).To(Receive(HavePrefix("Normal GiteaMigrated")))
+		stored := &api.Pattern{}
+		Expect(reconciler.Client.Get(context.Background(), patternNamespaced, stored)).To(Succeed())
+		Expect(stored.Spec.GitConfig.TargetRepo).To(Equal(giteaRepoURL))
+		Expect(stored.Spec.GitConfig.TargetRevision).To(BeEmpty())
+
+		Expect(reconciler.createGiteaInstance(current, nil)).To(Succeed())
+		Expect(recorder.Events).ToNot(Receive())
+		unchanged := &api.Pattern{}
+		Expect(reconciler.Client.Get(context.Background(), patternNamespaced, unchanged)).To(Succeed())
+		Expect(unchanged.ResourceVersion).To(Equal(stored.ResourceVersion))
+	})
+
+	It("should not fail without a recorder", func() {
+		reconciler := newFakeReconciler()
+		reconciler.recordNormalEvent(buildPatternManifest(), EventReasonApplicationCreated, "Created application %s", "foo")
+	})
+})
+
+var _ = Describe("pattern controller - reconcileNamespaceConflicts", func() {
+	var (
+		pattern   *api.Pattern
+		gitDir    string
+		older     *api.Pattern
+		yesterday metav1.Time
+	)
+
+	BeforeEach(func() {
+		var err error
+		gitDir, err = os.MkdirTemp("", "vp-namespaces")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(os.WriteFile(filepath.Join(gitDir, "values-global.yaml"),
+			[]byte("clusterGroup:\n  namespaces:\n  - vault\n  - lob-app\n"), 0600)).To(Succeed())
+
+		yesterday = metav1.NewTime(time.Now().Add(-24 * time.Hour))
+		pattern = buildPatternManifest()
+		pattern.Name = "line-of-business"
+		pattern.CreationTimestamp = metav1.Now()
+		pattern.Status.LocalCheckoutPath = gitDir
+		older = buildPatternManifest()
+		older.Name = "platform"
+		older.CreationTimestamp = yesterday
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(gitDir)
+	})
+
+	It("should record the namespaces of the pattern", func() {
+		reconciler := newFakeReconciler(pattern)
+		changed, err := reconciler.reconcileNamespaceConflicts(pattern)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(pattern.Status.Namespaces).To(Equal([]string{"lob-app", "vault"}))
+
+		changed, err = reconciler.reconcileNamespaceConflicts(pattern)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeFalse())
+	})
+
+	It("should fail when an older pattern deploys to the same namespaces", func() {
+		older.Status.Namespaces = []string{"openshift-gitops", "vault"}
+		reconciler := newFakeReconciler(pattern, older)
+		_, err := reconciler.reconcileNamespaceConflicts(pattern)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("vault"))
+		Expect(err.Error()).To(ContainSubstring(older.Name))
+		Expect(pattern.Status.Namespaces).To(Equal([]string{"lob-app", "vault"}))
+	})
+
+	It("should not fail the older pattern", func() {
+		pattern.Status.Namespaces = []string{"vault"}
+		older.Status.LocalCheckoutPath = gitDir
+		reconciler := newFakeReconciler(pattern, older)
+		_, err := reconciler.reconcileNamespaceConflicts(older)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should fail when an older pattern in another namespace has the same name", func() {
+		older.Name = pattern.Name
+		older.Namespace = "platform"
+		reconciler := newFakeReconciler(pattern, older)
+		_, err := reconciler.reconcileNamespaceConflicts(pattern)
+		Expect(err).To(MatchError(ContainSubstring("platform/" + pattern.Name)))
+	})
+
+	It("should allow patterns deploying to distinct namespaces", func() {
+		older.Status.Namespaces = []string{"openshift-gitops"}
+		reconciler := newFakeReconciler(pattern, older)
+		_, err := reconciler.reconcileNamespaceConflicts(pattern)
+		Expect(err).ToNot(HaveOccurred())
 	})
 })
 
@@ -467,7 +1073,7 @@ var _ = Describe("pattern controller - applyDefaults", func() {
 
 	It("should set cluster info from configClient", func() {
 		p := buildPatternManifest()
-		output, err := reconciler.applyDefaults(p)
+		output, err := reconciler.applyDefaults(p, nil)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(output.Status.ClusterPlatform).To(Equal("AWS"))
 		Expect(output.Status.ClusterVersion).To(Equal("4.10"))
@@ -475,7 +1081,7 @@ var _ = Describe("pattern controller - applyDefaults", func() {
 
 	It("should set the cluster domain from ingress", func() {
 		p := buildPatternManifest()
-		output, err := reconciler.applyDefaults(p)
+		output, err := reconciler.applyDefaults(p, nil)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(output.Status.AppClusterDomain).To(Equal("hello.world"))
 	})
@@ -483,7 +1089,7 @@ var _ = Describe("pattern controller - applyDefaults", func() {
 	It("should default TargetRevision to HEAD when empty", func() {
 		p := buildPatternManifest()
 		p.Spec.GitConfig.TargetRevision = ""
-		output, err := reconciler.applyDefaults(p)
+		output, err := reconciler.applyDefaults(p, nil)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(output.Spec.GitConfig.TargetRevision).To(Equal(GitHEAD))
 	})
@@ -491,7 +1097,7 @@ var _ = Describe("pattern controller - applyDefaults", func() {
 	It("should preserve TargetRevision when set", func() {
 		p := buildPatternManifest()
 		p.Spec.GitConfig.TargetRevision = "v1.0.0"
-		output, err := reconciler.applyDefaults(p)
+		output, err := reconciler.applyDefaults(p, nil)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(output.Spec.GitConfig.TargetRevision).To(Equal("v1.0.0"))
 	})
@@ -499,7 +1105,7 @@ var _ = Describe("pattern controller - applyDefaults", func() {
 	It("should default OriginRevision to HEAD when empty", func() {
 		p := buildPatternManifest()
 		p.Spec.GitConfig.OriginRevision = ""
-		output, err := reconciler.applyDefaults(p)
+		output, err := reconciler.applyDefaults(p, nil)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(output.Spec.GitConfig.OriginRevision).To(Equal(GitHEAD))
 	})
@@ -507,7 +1113,7 @@ var _ = Describe("pattern controller - applyDefaults", func() {
 	It("should default MultiSourceConfig.Enabled to true when nil", func() {
 		p := buildPatternManifest()
 		p.Spec.MultiSourceConfig.Enabled = nil
-		output, err := reconciler.applyDefaults(p)
+		output, err := reconciler.applyDefaults(p, nil)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(output.Spec.MultiSourceConfig.Enabled).ToNot(BeNil())
 		Expect(*output.Spec.MultiSourceConfig.Enabled).To(BeTrue())
@@ -517,7 +1123,7 @@ var _ = Describe("pattern controller - applyDefaults", func() {
 		p := buildPatternManifest()
 		p.Spec.ClusterGroupName = "hub"
 		p.Spec.Variant = ""
-		output, err := reconciler.applyDefaults(p)
+		output, err := reconciler.applyDefaults(p, nil)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(output.Spec.ClusterGroupName).To(Equal("hub"))
 	})
@@ -526,7 +1132,7 @@ var _ = Describe("pattern controller - applyDefaults", func() {
 		p := buildPatternManifest()
 		p.Spec.ClusterGroupName = ""
 		p.Spec.Variant = "factory"
-		output, err := reconciler.applyDefaults(p)
+		output, err := reconciler.applyDefaults(p, nil)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(output.Spec.ClusterGroupName).To(Equal("factory"))
 	})
@@ -535,7 +1141,7 @@ var _ = Describe("pattern controller - applyDefaults", func() {
 		p := buildPatternManifest()
 		p.Spec.ClusterGroupName = "hub"
 		p.Spec.Variant = "factory"
-		output, err := reconciler.applyDefaults(p)
+		output, err := reconciler.applyDefaults(p, nil)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(output.Spec.ClusterGroupName).To(Equal("factory"))
 	})
@@ -543,7 +1149,7 @@ var _ = Describe("pattern controller - applyDefaults", func() {
 	It("should default HelmRepoUrl when empty", func() {
 		p := buildPatternManifest()
 		p.Spec.MultiSourceConfig.HelmRepoUrl = ""
-		output, err := reconciler.applyDefaults(p)
+		output, err := reconciler.applyDefaults(p, nil)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(output.Spec.MultiSourceConfig.HelmRepoUrl).To(Equal("https://charts.validatedpatterns.io/"))
 	})
@@ -551,7 +1157,7 @@ var _ = Describe("pattern controller - applyDefaults", func() {
 	It("should initialize GitOpsConfig when nil", func() {
 		p := buildPatternManifest()
 		p.Spec.GitOpsConfig = nil
-		output, err := reconciler.applyDefaults(p)
+		output, err := reconciler.applyDefaults(p, nil)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(output.Spec.GitOpsConfig).ToNot(BeNil())
 	})
@@ -559,7 +1165,7 @@ var _ = Describe("pattern controller - applyDefaults", func() {
 	It("should extract hostname from TargetRepo when Hostname is empty", func() {
 		p := buildPatternManifest()
 		p.Spec.GitConfig.Hostname = ""
-		output, err := reconciler.applyDefaults(p)
+		output, err := reconciler.applyDefaults(p, nil)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(output.Spec.GitConfig.Hostname).To(Equal("target.url"))
 	})
@@ -567,14 +1173,14 @@ var _ = Describe("pattern controller - applyDefaults", func() {
 	It("should preserve hostname when already set", func() {
 		p := buildPatternManifest()
 		p.Spec.GitConfig.Hostname = "custom.hostname.io"
-		output, err := reconciler.applyDefaults(p)
+		output, err := reconciler.applyDefaults(p, nil)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(output.Spec.GitConfig.Hostname).To(Equal("custom.hostname.io"))
 	})
 
 	It("should set LocalCheckoutPath", func() {
 		p := buildPatternManifest()
-		output, err := reconciler.applyDefaults(p)
+		output, err := reconciler.applyDefaults(p, nil)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(output.Status.LocalCheckoutPath).ToNot(BeEmpty())
 	})
@@ -655,3 +1261,220 @@ var _ = Describe("pattern controller - fetching pattern", func() {
 		Expect(err).To(HaveOccurred())
 	})
 })
+
+var _ = Describe("pattern controller - application status", func() {
+	It("should copy the sync, operation and destination details of an application", func() {
+		finished := metav1.Now()
+		app := &argoapi.Application{
+			ObjectMeta: metav1.ObjectMeta{Name: "hello-world", Namespace: "openshift-gitops"},
+			Spec: argoapi.ApplicationSpec{
+				Destination: argoapi.ApplicationDestination{Name: "in-cluster", Namespace: "hello"},
+			},
+			Status: argoapi.ApplicationStatus{
+				Health: argoapi.AppHealthStatus{Status: "Degraded", Message: "Deployment exceeded its progress deadline"},
+				Sync:   argoapi.SyncStatus{Status: "OutOfSync", Revisions: []string{"abc123", "def456"}},
+				OperationState: &argoapi.OperationState{
+					Phase: "Failed", Message: "one or more objects failed to apply", FinishedAt: &finished,
+				},
+				Resources: []argoapi.ResourceStatus{
+					{Name: "a", Status: argoapi.SyncStatusCodeOutOfSync},
+					{Name: "b", Status: argoapi.SyncStatusCodeSynced},
+					{Name: "c", Status: argoapi.SyncStatusCodeOutOfSync},
+				},
+			},
+		}
+		Expect(newApplicationInfo(app)).To(Equal(api.PatternApplicationInfo{
+			Name:                 "hello-world",
+			Namespace:            "openshift-gitops",
+			AppSyncStatus:        "OutOfSync",
+			AppHealthStatus:      "Degraded",
+			AppHealthMessage:     "Deployment exceeded its progress deadline",
+			SyncedRevision:       "abc123,def456",
+			LastSyncedAt:         &finished,
+			OperationPhase:       "Failed",
+			OperationMessage:     "one or more objects failed to apply",
+			DestinationServer:    "in-cluster",
+			DestinationNamespace: "hello",
+			OutOfSyncResources:   2,
+		}))
+	})
+
+	It("should truncate long messages", func() {
+		message := strings.Repeat("é", api.MaxStatusMessageLength)
+		truncated := truncateMessage(message)
+		Expect(len(truncated)).To(BeNumerically("<=", api.MaxStatusMessageLength))
+		Expect(utf8.ValidString(truncated)).To(BeTrue())
+		Expect(truncated).To(HaveSuffix("..."))
+		Expect(truncateMessage("short")).To(Equal("short"))
+	})
+
+	It("should drop healthy applications first when there are too many", func() {
+		apps := []api.PatternApplicationInfo{
+			{Name: "a", AppSyncStatus: "Synced", AppHealthStatus: "Healthy"},
+			{Name: "b", AppSyncStatus: "Synced", AppHealthStatus: "Degraded"},
+			{Name: "c", AppSyncStatus: "Synced", AppHealthStatus: "Healthy"},
+			{Name: "d", AppSyncStatus: "OutOfSync", AppHealthStatus: "Healthy"},
+		}
+		Expect(boundApplications(apps, 4)).To(HaveLen(4))
+		names := func(apps []api.PatternApplicationInfo) []string {
+			var n []string
+			for _, app := range apps {
+				n = append(n, app.Name)
+			}
+			return n
+		}
+		Expect(names(boundApplications(apps, 3))).To(Equal([]string{"a", "b", "d"}))
+		Expect(names(boundApplications(apps, 1))).To(Equal([]string{"b"}))
+	})
+})
+
+var _ = Describe("pattern controller - application watch", func() {
+	It("should map an application to the pattern named by its label", func() {
+		pattern := buildPatternManifest()
+		other := buildPatternManifest()
+		other.Name = "other"
+		reconciler := newFakeReconciler()
+		reconciler.Client = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(pattern, other).Build()
+
+		app := &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{
+			Name:      "hello-world",
+			Namespace: "openshift-gitops",
+			Labels:    map[string]string{PatternApplicationLabel: foo},
+		}}
+		Expect(reconciler.patternsForLabel(context.Background(), app)).To(ConsistOf(
+			reconcile.Request{NamespacedName: patternNamespaced},
+		))
+
+		app.Labels[PatternApplicationLabel] = "unknown"
+		Expect(reconciler.patternsForLabel(context.Background(), app)).To(BeEmpty())
+		delete(app.Labels, PatternApplicationLabel)
+		Expect(reconciler.patternsForLabel(context.Background(), app)).To(BeEmpty())
+	})
+})
+
+var _ = Describe("pattern controller - git auth secret", func() {
+	var (
+		pattern    *api.Pattern
+		reconciler *PatternReconciler
+		fullClient *kubeclient.Clientset
+	)
+
+	BeforeEach(func() {
+		pattern = buildPatternManifest()
+		pattern.Spec.GitConfig.TokenSecret = "git-token"
+		pattern.Spec.GitConfig.TokenSecretNamespace = "secrets"
+		reconciler = newFakeReconciler(pattern)
+		fullClient = kubeclient.NewSimpleClientset(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "git-token", Namespace: "secrets"},
+			Data:       map[string][]byte{"password": []byte("one")},
+		})
+		reconciler.fullClient = fullClient
+	})
+
+	getCopy := func() *corev1.Secret {
+		copied, err := fullClient.CoreV1().Secrets("openshift-gitops").Get(context.Background(), GitAuthSecretCopyName, metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		return copied
+	}
+
+	It("should copy the secret and follow its rotation", func() {
+		Expect(reconciler.syncAuthGitSecret(pattern, "openshift-gitops", false)).To(Succeed())
+		copied := getCopy()
+		Expect(copied.Data).To(HaveKeyWithValue("password", []byte("one")))
+		Expect(copied.Labels).To(HaveKeyWithValue(PatternApplicationLabel, foo))
+
+		source, _ := fullClient.CoreV1().Secrets("secrets").Get(context.Background(), "git-token", metav1.GetOptions{})
+		source.Data["password"] = []byte("two")
+		_, err := fullClient.CoreV1().Secrets("secrets").Update(context.Background(), source, metav1.UpdateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+
+		fullClient.ClearActions()
+		Expect(reconciler.syncAuthGitSecret(pattern, "openshift-gitops", false)).To(Succeed())
+		Expect(getCopy().Data).To(HaveKeyWithValue("password", []byte("two")))
+
+		// An up to date copy is left alone
+		fullClient.ClearActions()
+		Expect(reconciler.syncAuthGitSecret(pattern, "openshift-gitops", false)).To(Succeed())
+		for _, action := range fullClient.Actions() {
+			Expect(action.GetVerb()).To(Equal("get"))
+		}
+	})
+
+	It("should remove the copy once the pattern no longer references a secret", func() {
+		copied := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name: GitAuthSecretCopyName, Namespace: "openshift-gitops", Labels: map[string]string{PatternApplicationLabel: foo},
+		}}
+		perPattern := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name: gitAuthSecretCopyName(pattern, true), Namespace: "openshift-gitops", Labels: map[string]string{PatternApplicationLabel: foo},
+		}}
+		other := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name: GitAuthSecretCopyName, Namespace: "other-ns", Labels: map[string]string{PatternApplicationLabel: "other"},
+		}}
+		reconciler.Client = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(copied, perPattern, other).Build()
+		pattern.Spec.GitConfig.TokenSecret = ""
+
+		Expect(reconciler.syncAuthGitSecret(pattern, "openshift-gitops", false)).To(Succeed())
+		Expect(reconciler.syncAuthGitSecret(pattern, "other-ns", false)).To(Succeed())
+		Expect(reconciler.syncAuthGitSecret(pattern, "missing-ns", false)).To(Succeed())
+
+		err := reconciler.Client.Get(context.Background(), client.ObjectKeyFromObject(copied), &corev1.Secret{})
+		Expect(kerrors.IsNotFound(err)).To(BeTrue())
+		err = reconciler.Client.Get(context.Background(), client.ObjectKeyFromObject(perPattern), &corev1.Secret{})
+		Expect(kerrors.IsNotFound(err)).To(BeTrue())
+		Expect(reconciler.Client.Get(context.Background(), client.ObjectKeyFromObject(other), &corev1.Secret{})).To(Succeed())
+	})
+
+	It("should keep the copies of patterns sharing the Argo namespace apart", func() {
+		legacy := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name: GitAuthSecretCopyName, Namespace: "openshift-gitops", Labels: map[string]string{PatternApplicationLabel: foo},
+		}}
+		reconciler.Client = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(legacy).Build()
+		second := buildPatternManifest()
+		second.Name = "line-of-business"
+		second.Spec.GitConfig = pattern.Spec.GitConfig
+
+		Expect(reconciler.syncAuthGitSecret(pattern, "openshift-gitops", true)).To(Succeed())
+		Expect(reconciler.syncAuthGitSecret(second, "openshift-gitops", true)).To(Succeed())
+		for _, p := range []*api.Pattern{pattern, second} {
+			copied, err := fullClient.CoreV1().Secrets("openshift-gitops").Get(context.Background(), gitAuthSecretCopyName(p, true), metav1.GetOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(copied.Labels).To(HaveKeyWithValue(PatternApplicationLabel, p.Name))
+		}
+		// The well-known copy is used outside the operator and is left in place
+		Expect(reconciler.Client.Get(context.Background(), client.ObjectKeyFromObject(legacy), &corev1.Secret{})).To(Succeed())
+	})
+
+	It("should reconcile the patterns that reference a secret or own a copy", func() {
+		source := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "git-token", Namespace: "secrets"}}
+		Expect(reconciler.enqueuePatternsForGitAuthSecret(context.Background(), source)).To(ConsistOf(
+			reconcile.Request{NamespacedName: patternNamespaced},
+		))
+		unrelated := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "git-token", Namespace: "elsewhere"}}
+		Expect(reconciler.enqueuePatternsForGitAuthSecret(context.Background(), unrelated)).To(BeEmpty())
+		copied := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name: gitAuthSecretCopyName(pattern, true), Namespace: "openshift-gitops", Labels: map[string]string{PatternApplicationLabel: foo},
+		}}
+		Expect(reconciler.enqueuePatternsForGitAuthSecret(context.Background(), copied)).To(ConsistOf(
+			reconcile.Request{NamespacedName: patternNamespaced},
+		))
+	})
+
+	It("should map the metadata of the watched Secrets and ConfigMaps to the patterns", func() {
+		pattern.Spec.GitConfig.CABundleFrom = &api.PatternParameterSource{
+			SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "credentials"}, Key: "ca.crt"},
+		}
+		reconciler.Client = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(pattern).Build()
+		metadata := func(name, namespace string) *metav1.PartialObjectMetadata {
+			return &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+		}
+
+		Expect(reconciler.enqueuePatternsForSecret(context.Background(), metadata("git-token", "secrets"))).To(ConsistOf(
+			reconcile.Request{NamespacedName: patternNamespaced},
+		))
+		Expect(reconciler.enqueuePatternsForSecret(context.Background(), metadata("credentials", pattern.Namespace))).To(ConsistOf(
+			reconcile.Request{NamespacedName: patternNamespaced},
+		))
+		// A ConfigMap of the same name is not the source of the CA bundle
+		Expect(reconciler.enqueuePatternsForConfigMap(context.Background(), metadata("credentials", pattern.Namespace))).To(BeEmpty())
+	})
+})
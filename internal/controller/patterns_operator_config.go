@@ -23,26 +23,63 @@ const (
 	configKeyCustomHealthCheck = "gitops.customHealthChecks"
 	configKeyArgoRBAC          = "gitops.argoRBAC"
 	configKeyCustomArgoYaml    = "gitops.customArgoYaml"
-	configMapKind              = "ConfigMap"
-	boolTrue                   = "true"
-	boolFalse                  = "false"
+	configKeyAllowMultiple     = "patterns.allowMultiple"
+	configKeyProvisioner       = "gitops.provisioner"
+	// Only read with the external provisioner
+	configKeyExternalArgoNamespace = "gitops.argoNamespace"
+	configKeyExternalArgoName      = "gitops.argoName"
+	// Only read with the argocd-operator provisioner
+	configKeyArgoCDOperatorCatalogSource   = "argocdOperator.catalogSource"
+	configKeyArgoCDOperatorSourceNamespace = "argocdOperator.sourceNamespace"
+	configKeyArgoCDOperatorChannel         = "argocdOperator.channel"
+	configKeyArgoCDOperatorNamespace       = "argocdOperator.namespace"
+	// ConfigMap whose entries are GPG or SSH public keys trusted to sign the target revision of every pattern,
+	// "name" in the operator namespace or "namespace/name". argocd-gpg-keys-cm can be used as is
+	configKeySigningKeysConfigMap = "git.signingKeysConfigMap"
+	// ConfigMap with an ssh_known_hosts key, "name" in the operator namespace or "namespace/name"
+	configKeySSHKnownHostsConfigMap = "git.sshKnownHostsConfigMap"
+	configKeyClusterID              = "cluster.id"
+	configKeyClusterName            = "cluster.name"
+	configKeyClusterDomain          = "cluster.domain"
+	configKeyClusterAppDomain       = "cluster.appDomain"
+	configKeyClusterPlatform        = "cluster.platform"
+	configKeyClusterVersion         = "cluster.version"
+	configMapKind                   = "ConfigMap"
+	boolTrue                        = "true"
+	boolFalse                       = "false"
 )
 
 var DefaultPatternsOperatorConfig = PatternsOperatorConfig{
-	configKeyCatalogSource:     GitOpsDefaultCatalogSource,
-	configKeyChannel:           GitOpsDefaultChannel,
-	configKeySourceNamespace:   GitOpsDefaultCatalogSourceNamespace,
-	configKeyApprovalPlan:      GitOpsDefaultApprovalPlan,
-	configKeyCSV:               GitOpsDefaultCSV,
-	configKeyAdditionalAdmins:  "",
-	configKeyHealthCheck:       boolFalse,
-	configKeyCustomHealthCheck: "",
-	configKeyArgoRBAC:          "",
-	configKeyCustomArgoYaml:    "",
-	"gitea.chartName":          GiteaChartName,
-	"gitea.helmRepoUrl":        GiteaHelmRepoUrl,
-	"gitea.chartVersion":       GiteaDefaultChartVersion,
-	"catalog.image":            "",
+	configKeyCatalogSource:                 GitOpsDefaultCatalogSource,
+	configKeyChannel:                       GitOpsDefaultChannel,
+	configKeySourceNamespace:               GitOpsDefaultCatalogSourceNamespace,
+	configKeyApprovalPlan:                  GitOpsDefaultApprovalPlan,
+	configKeyCSV:                           GitOpsDefaultCSV,
+	configKeyAdditionalAdmins:              "",
+	configKeyHealthCheck:                   boolFalse,
+	configKeyCustomHealthCheck:             "",
+	configKeyArgoRBAC:                      "",
+	configKeyCustomArgoYaml:                "",
+	configKeyAllowMultiple:                 boolFalse,
+	configKeyProvisioner:                   GitOpsProvisionerOpenShiftGitOps,
+	configKeyExternalArgoNamespace:         ExternalArgoDefaultNamespace,
+	configKeyExternalArgoName:              ExternalArgoDefaultName,
+	configKeyArgoCDOperatorCatalogSource:   ArgoCDOperatorDefaultCatalogSource,
+	configKeyArgoCDOperatorSourceNamespace: ArgoCDOperatorDefaultCatalogSourceNamespace,
+	configKeyArgoCDOperatorChannel:         ArgoCDOperatorDefaultChannel,
+	configKeyArgoCDOperatorNamespace:       ArgoCDOperatorDefaultSubscriptionNamespace,
+	configKeySSHKnownHostsConfigMap:        "",
+	configKeySigningKeysConfigMap:          "",
+	configKeyClusterID:                     "",
+	configKeyClusterName:                   "",
+	configKeyClusterDomain:                 "",
+	configKeyClusterAppDomain:              "",
+	configKeyClusterPlatform:               "",
+	configKeyClusterVersion:                "",
+	"gitea.chartName":                      GiteaChartName,
+	"gitea.helmRepoUrl":                    GiteaHelmRepoUrl,
+	"gitea.chartVersion":                   GiteaDefaultChartVersion,
+	"catalog.image":                        "",
 }
 
 func (g PatternsOperatorConfig) getStringValue(k string) string {
@@ -94,3 +131,14 @@ func GetPatternsOperatorConfigMap(ctx context.Context, cl client.Client) (*corev
 	}
 	return &configMap, nil
 }
+
+// AllowMultiplePatterns returns true when the operator configuration opts in to running
+// more than one Pattern per cluster
+func AllowMultiplePatterns(ctx context.Context, cl client.Client) bool {
+	var config PatternsOperatorConfig
+	configMap, err := GetPatternsOperatorConfigMap(ctx, cl)
+	if err == nil && configMap != nil {
+		config = configMap.Data
+	}
+	return config.getBoolValue(configKeyAllowMultiple)
+}
@@ -115,6 +115,7 @@ var _ = Describe("DefaultPatternsOperatorConfig", func() {
 			"gitea.chartVersion",
 			"catalog.image",
 			"gitops.argoRBAC",
+			"patterns.allowMultiple",
 		}
 		for _, key := range expectedKeys {
 			Expect(DefaultPatternsOperatorConfig).To(HaveKey(key))
@@ -0,0 +1,240 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	configclient "github.com/openshift/client-go/config/clientset/versioned"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	// API group that tells OpenShift apart from upstream Kubernetes
+	OpenShiftConfigGroup = "config.openshift.io"
+	// API group of the Operator Lifecycle Manager, used to install the GitOps operator
+	OLMGroup = "operators.coreos.com"
+
+	// Platform reported when the Nodes do not carry a providerID, same as OpenShift on bare metal
+	defaultKubernetesPlatform = "None"
+	// Name ACM gives to the hub cluster, used when nothing else names the cluster
+	defaultKubernetesClusterName = "local-cluster"
+)
+
+// ProviderID schemes of the Nodes and the platform they run on
+var providerIDPlatforms = map[string]string{
+	"aws":       "AWS",
+	"azure":     "Azure",
+	"gce":       "GCP",
+	"ibm":       "IBMCloud",
+	"openstack": "OpenStack",
+	"vsphere":   "VSphere",
+	"kind":      "Kind",
+	"k3s":       "K3s",
+}
+
+// ClusterInfo describes the cluster the operator runs on
+type ClusterInfo struct {
+	ID       string
+	Platform string
+	// major.minor
+	Version string
+	Name    string
+	// Base domain of the cluster, mycluster.example.com
+	Domain string
+	// Wildcard domain of the ingress, apps.mycluster.example.com
+	AppDomain string
+}
+
+// ClusterPlatform reads the cluster information from the APIs of the platform the operator runs on
+type ClusterPlatform interface {
+	// IsOpenShift reports whether the OpenShift APIs are available. Routes, the console, ConsoleLinks
+	// and the trusted CA bundle injection are skipped otherwise
+	IsOpenShift() bool
+	ClusterInfo(ctx context.Context) (*ClusterInfo, error)
+}
+
+// detectClusterPlatform returns the OpenShift platform when the config.openshift.io API is served,
+// and the upstream Kubernetes one otherwise
+func detectClusterPlatform(fullClient kubernetes.Interface, configClient configclient.Interface) (ClusterPlatform, error) {
+	openShift, err := haveAPIVersion(fullClient, OpenShiftConfigGroup, "v1")
+	if err != nil {
+		return nil, err
+	}
+	if !openShift {
+		log.Printf("API group %s is not available, running on upstream Kubernetes", OpenShiftConfigGroup)
+		return &kubernetesPlatform{fullClient: fullClient}, nil
+	}
+	return &openShiftPlatform{configClient: configClient}, nil
+}
+
+// IsOpenShiftCluster reports whether the cluster behind cfg serves the OpenShift APIs
+func IsOpenShiftCluster(cfg *rest.Config) (bool, error) {
+	fullClient, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return false, err
+	}
+	return haveAPIVersion(fullClient, OpenShiftConfigGroup, "v1")
+}
+
+type openShiftPlatform struct {
+	configClient configclient.Interface
+}
+
+func (o *openShiftPlatform) IsOpenShift() bool {
+	return true
+}
+
+func (o *openShiftPlatform) ClusterInfo(ctx context.Context) (*ClusterInfo, error) {
+	info := &ClusterInfo{}
+
+	// Cluster ID and version
+	// oc get clusterversion/version -o yaml
+	cv, err := o.configClient.ConfigV1().ClusterVersions().Get(ctx, "version", metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	info.ID = string(cv.Spec.ClusterID)
+	v, err := getCurrentClusterVersion(cv)
+	if err != nil {
+		return nil, err
+	}
+	info.Version = fmt.Sprintf("%d.%d", v.Major(), v.Minor())
+
+	// Cluster platform
+	// oc get Infrastructure.config.openshift.io/cluster  -o jsonpath='{.spec.platformSpec.type}'
+	clusterInfra, err := o.configClient.ConfigV1().Infrastructures().Get(ctx, searchFilterCluster, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	info.Platform = string(clusterInfra.Spec.PlatformSpec.Type)
+
+	// Derive cluster and domain names
+	// oc get Ingress.config.openshift.io/cluster -o jsonpath='{.spec.domain}'
+	clusterIngress, err := o.configClient.ConfigV1().Ingresses().Get(ctx, searchFilterCluster, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	info.AppDomain = clusterIngress.Spec.Domain
+	info.Name, info.Domain = splitAppDomain(info.AppDomain)
+	return info, nil
+}
+
+type kubernetesPlatform struct {
+	fullClient kubernetes.Interface
+}
+
+func (k *kubernetesPlatform) IsOpenShift() bool {
+	return false
+}
+
+// ClusterInfo derives what it can from the cluster. Upstream Kubernetes has no notion of a cluster name or
+// domain, they are expected in the operator configuration
+func (k *kubernetesPlatform) ClusterInfo(ctx context.Context) (*ClusterInfo, error) {
+	info := &ClusterInfo{Platform: defaultKubernetesPlatform}
+
+	// The UID of kube-system is the usual stand-in for a cluster ID
+	ns, err := k.fullClient.CoreV1().Namespaces().Get(ctx, "kube-system", metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	info.ID = string(ns.UID)
+
+	version, err := k.fullClient.Discovery().ServerVersion()
+	if err != nil {
+		return nil, err
+	}
+	// Managed offerings report minor versions such as "29+"
+	info.Version = version.Major + "." + strings.TrimRight(version.Minor, "+")
+
+	nodes, err := k.fullClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{Limit: 1})
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes.Items) > 0 {
+		info.Platform = platformFromProviderID(nodes.Items[0].Spec.ProviderID)
+	}
+	return info, nil
+}
+
+// platformFromProviderID maps the scheme of a Node providerID, aws:///us-east-1a/i-0123, to the platform names OpenShift uses
+func platformFromProviderID(providerID string) string {
+	scheme, _, found := strings.Cut(providerID, "://")
+	if !found || scheme == "" {
+		return defaultKubernetesPlatform
+	}
+	if platform, ok := providerIDPlatforms[scheme]; ok {
+		return platform
+	}
+	return scheme
+}
+
+// splitAppDomain derives the cluster name and domain from the ingress domain, apps.mycluster.example.com
+// gives mycluster and mycluster.example.com. A domain with a single label is used as is
+func splitAppDomain(appDomain string) (name, domain string) {
+	ss := strings.Split(appDomain, ".")
+	if len(ss) < 2 {
+		return appDomain, appDomain
+	}
+	return ss[1], strings.Join(ss[1:], ".")
+}
+
+// getClusterInfo reads the cluster information from the platform and applies the overrides of the operator
+// configuration on top
+func getClusterInfo(ctx context.Context, platform ClusterPlatform, config PatternsOperatorConfig) (*ClusterInfo, error) {
+	info, err := platform.ClusterInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	overrides := map[string]*string{
+		configKeyClusterID:        &info.ID,
+		configKeyClusterPlatform:  &info.Platform,
+		configKeyClusterVersion:   &info.Version,
+		configKeyClusterName:      &info.Name,
+		configKeyClusterDomain:    &info.Domain,
+		configKeyClusterAppDomain: &info.AppDomain,
+	}
+	for key, field := range overrides {
+		if v := config.getStringValue(key); v != "" {
+			*field = v
+		}
+	}
+
+	switch {
+	case info.Domain == "" && info.AppDomain != "":
+		_, info.Domain = splitAppDomain(info.AppDomain)
+	case info.AppDomain == "" && info.Domain != "":
+		info.AppDomain = "apps." + info.Domain
+	}
+	if info.Name == "" {
+		info.Name = defaultKubernetesClusterName
+		if info.Domain != "" {
+			info.Name, _, _ = strings.Cut(info.Domain, ".")
+		}
+	}
+	if info.Domain == "" {
+		logOnce(fmt.Sprintf("The cluster domain is unknown, set %s in the %s ConfigMap", configKeyClusterDomain, OperatorConfigMap))
+	}
+	return info, nil
+}
+
+// clusterPlatform detects the platform on first use, the APIs of a cluster do not change underneath a running operator
+func (r *PatternReconciler) clusterPlatform() (ClusterPlatform, error) {
+	if r.platform == nil {
+		platform, err := detectClusterPlatform(r.fullClient, r.configClient)
+		if err != nil {
+			return nil, err
+		}
+		r.platform = platform
+	}
+	return r.platform, nil
+}
+
+// isOpenShift is false when the platform could not be detected, Reconcile does not get past a failed detection
+func (r *PatternReconciler) isOpenShift() bool {
+	platform, err := r.clusterPlatform()
+	return err == nil && platform.IsOpenShift()
+}
@@ -0,0 +1,99 @@
+package controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/version"
+	discoveryfake "k8s.io/client-go/discovery/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+var _ = Describe("cluster platform", func() {
+	var clientset *kubefake.Clientset
+
+	BeforeEach(func() {
+		clientset = kubefake.NewSimpleClientset(
+			&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system", UID: types.UID("1234-abcd")}},
+			&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}, Spec: v1.NodeSpec{ProviderID: "aws:///us-east-1a/i-0123"}},
+		)
+		clientset.Discovery().(*discoveryfake.FakeDiscovery).FakedServerVersion = &version.Info{Major: "1", Minor: "31+"}
+	})
+
+	It("should detect upstream Kubernetes when the OpenShift config API is absent", func() {
+		platform, err := detectClusterPlatform(clientset, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(platform.IsOpenShift()).To(BeFalse())
+
+		clientset.Discovery().(*discoveryfake.FakeDiscovery).Resources = []*metav1.APIResourceList{
+			{GroupVersion: OpenShiftConfigGroup + "/v1"},
+		}
+		platform, err = detectClusterPlatform(clientset, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(platform.IsOpenShift()).To(BeTrue())
+	})
+
+	It("should derive the cluster info from the namespace, the server version and the nodes", func() {
+		info, err := getClusterInfo(context.Background(), &kubernetesPlatform{fullClient: clientset}, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(*info).To(Equal(ClusterInfo{
+			ID:       "1234-abcd",
+			Platform: "AWS",
+			Version:  "1.31",
+			Name:     defaultKubernetesClusterName,
+		}))
+	})
+
+	It("should apply the overrides of the operator configuration", func() {
+		config := PatternsOperatorConfig{
+			configKeyClusterDomain:   "edge1.example.com",
+			configKeyClusterPlatform: "BareMetal",
+		}
+		info, err := getClusterInfo(context.Background(), &kubernetesPlatform{fullClient: clientset}, config)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(info.Platform).To(Equal("BareMetal"))
+		Expect(info.Name).To(Equal("edge1"))
+		Expect(info.Domain).To(Equal("edge1.example.com"))
+		Expect(info.AppDomain).To(Equal("apps.edge1.example.com"))
+
+		config = PatternsOperatorConfig{configKeyClusterAppDomain: "apps.edge2.example.com", configKeyClusterName: "edge"}
+		info, err = getClusterInfo(context.Background(), &kubernetesPlatform{fullClient: clientset}, config)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(info.Name).To(Equal("edge"))
+		Expect(info.Domain).To(Equal("edge2.example.com"))
+	})
+
+	DescribeTable("mapping a node providerID to a platform",
+		func(providerID, expected string) {
+			Expect(platformFromProviderID(providerID)).To(Equal(expected))
+		},
+		Entry("aws", "aws:///us-east-1a/i-0123", "AWS"),
+		Entry("gce", "gce://project/us-central1-a/node", "GCP"),
+		Entry("kind", "kind://docker/kind/kind-control-plane", "Kind"),
+		Entry("unknown provider", "equinix://1234", "equinix"),
+		Entry("no providerID", "", defaultKubernetesPlatform),
+	)
+
+	DescribeTable("splitting the ingress domain",
+		func(appDomain, name, domain string) {
+			n, d := splitAppDomain(appDomain)
+			Expect(n).To(Equal(name))
+			Expect(d).To(Equal(domain))
+		},
+		Entry("full domain", "apps.mycluster.example.com", "mycluster", "mycluster.example.com"),
+		Entry("two labels", "hello.world", "world", "world"),
+		Entry("single label", "localhost", "localhost", "localhost"),
+		Entry("empty", "", "", ""),
+	)
+
+	It("should not create the gitea instance without OpenShift routes", func() {
+		reconciler := newFakeReconciler()
+		reconciler.platform = &kubernetesPlatform{fullClient: clientset}
+		err := reconciler.createGiteaInstance(buildPatternManifest(), nil)
+		Expect(err).To(MatchError(ContainSubstring("requires OpenShift")))
+	})
+})
@@ -0,0 +1,145 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	nethttp "net/http"
+	"net/url"
+	"sync"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"golang.org/x/net/http/httpproxy"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	api "github.com/hybrid-cloud-patterns/patterns-operator/api/v1alpha1"
+)
+
+const (
+	// Namespace of the ConfigMap named in spec.trustedCA of the cluster Proxy
+	proxyTrustedCANamespace = "openshift-config"
+	proxyTrustedCAKey       = "ca-bundle.crt"
+)
+
+// clusterProxy is the effective configuration of the cluster wide config.openshift.io/v1 Proxy
+type clusterProxy struct {
+	// PEM encoded CAs of spec.trustedCA, needed when the proxy intercepts TLS
+	trustedCA []byte
+	proxyFunc func(*url.URL) (*url.URL, error)
+}
+
+var (
+	activeProxyMutex sync.RWMutex
+	// nil when the cluster has no proxy, outbound calls then honor the proxy environment of the operator
+	activeProxy *clusterProxy
+)
+
+func newClusterProxy(httpProxy, httpsProxy, noProxy string, trustedCA []byte) *clusterProxy {
+	return &clusterProxy{
+		trustedCA: trustedCA,
+		proxyFunc: (&httpproxy.Config{
+			HTTPProxy:  httpProxy,
+			HTTPSProxy: httpsProxy,
+			NoProxy:    noProxy,
+		}).ProxyFunc(),
+	}
+}
+
+func setClusterProxy(p *clusterProxy) {
+	activeProxyMutex.Lock()
+	defer activeProxyMutex.Unlock()
+	activeProxy = p
+}
+
+func getClusterProxy() *clusterProxy {
+	activeProxyMutex.RLock()
+	defer activeProxyMutex.RUnlock()
+	return activeProxy
+}
+
+// proxyForRequest is the Proxy of every outbound transport. The cluster proxy is read on each request
+// so that long lived clients follow proxy changes
+func proxyForRequest(req *nethttp.Request) (*url.URL, error) {
+	p := getClusterProxy()
+	if p == nil {
+		return nethttp.ProxyFromEnvironment(req)
+	}
+	return p.proxyFunc(req.URL)
+}
+
+// proxyTrustedCA returns the CAs of the cluster proxy, nil without one
+func proxyTrustedCA() []byte {
+	if p := getClusterProxy(); p != nil {
+		return p.trustedCA
+	}
+	return nil
+}
+
+// reconcileClusterProxy reads the cluster Proxy into the configuration used by the outbound transports.
+// Upstream Kubernetes has no Proxy object, the environment of the operator applies there
+func (r *PatternReconciler) reconcileClusterProxy(ctx context.Context) error {
+	if !r.isOpenShift() {
+		return nil
+	}
+	proxy, err := r.configClient.ConfigV1().Proxies().Get(ctx, searchFilterCluster, metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		setClusterProxy(nil)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	setClusterProxy(clusterProxyFromObject(proxy, r.getProxyTrustedCA(proxy)))
+	return nil
+}
+
+// getProxyTrustedCA reads the ConfigMap of spec.trustedCA. The same CAs are merged into the trusted CA
+// bundle of openshift-config-managed, reading them directly covers the time before the merge
+func (r *PatternReconciler) getProxyTrustedCA(proxy *configv1.Proxy) []byte {
+	if proxy.Spec.TrustedCA.Name == "" {
+		return nil
+	}
+	ca, err := getConfigMapKey(r.fullClient, proxyTrustedCANamespace, proxy.Spec.TrustedCA.Name, proxyTrustedCAKey)
+	if err != nil {
+		log.Printf("Could not get the trusted CA of the cluster proxy: %v", err)
+		return nil
+	}
+	return []byte(ca)
+}
+
+// clusterProxyFromObject prefers the status of the Proxy, which holds what the cluster applies including the
+// computed noProxy entries of the cluster networks. The spec is used until the status is filled in
+func clusterProxyFromObject(proxy *configv1.Proxy, trustedCA []byte) *clusterProxy {
+	httpProxy, httpsProxy, noProxy := proxy.Status.HTTPProxy, proxy.Status.HTTPSProxy, proxy.Status.NoProxy
+	if httpProxy == "" && httpsProxy == "" {
+		httpProxy, httpsProxy, noProxy = proxy.Spec.HTTPProxy, proxy.Spec.HTTPSProxy, proxy.Spec.NoProxy
+	}
+	if httpProxy == "" && httpsProxy == "" {
+		return nil
+	}
+	// The proxy URLs may carry credentials, they are not logged
+	logOnce(fmt.Sprintf("Routing outbound connections through the proxy of the %s Proxy object", searchFilterCluster))
+	return newClusterProxy(httpProxy, httpsProxy, noProxy, trustedCA)
+}
+
+func isClusterProxy(obj client.Object) bool {
+	return obj != nil && obj.GetName() == searchFilterCluster
+}
+
+// enqueueAllPatterns enqueues reconcile for every Pattern, used for cluster wide configuration changes
+func (r *PatternReconciler) enqueueAllPatterns(ctx context.Context, _ client.Object) []reconcile.Request {
+	var list api.PatternList
+	if err := r.List(ctx, &list); err != nil {
+		ctrl.Log.Error(err, "failed to list Patterns after a cluster configuration change")
+		return nil
+	}
+	out := make([]reconcile.Request, 0, len(list.Items))
+	for i := range list.Items {
+		out = append(out, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&list.Items[i])})
+	}
+	return out
+}
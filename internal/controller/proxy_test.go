@@ -0,0 +1,97 @@
+package controllers
+
+import (
+	"context"
+	nethttp "net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	configv1 "github.com/openshift/api/config/v1"
+	configclient "github.com/openshift/client-go/config/clientset/versioned/fake"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+var _ = Describe("cluster proxy", func() {
+	var reconciler *PatternReconciler
+	var caPEM []byte
+
+	newProxy := func(spec configv1.ProxySpec, status configv1.ProxyStatus) *configv1.Proxy {
+		return &configv1.Proxy{ObjectMeta: metav1.ObjectMeta{Name: searchFilterCluster}, Spec: spec, Status: status}
+	}
+
+	proxyFor := func(rawURL string) string {
+		req, err := nethttp.NewRequest(nethttp.MethodGet, rawURL, nethttp.NoBody)
+		Expect(err).ToNot(HaveOccurred())
+		u, err := proxyForRequest(req)
+		Expect(err).ToNot(HaveOccurred())
+		if u == nil {
+			return ""
+		}
+		return u.String()
+	}
+
+	BeforeEach(func() {
+		_, caPEM, _ = newTestCA()
+		reconciler = newFakeReconciler()
+		reconciler.fullClient = kubefake.NewSimpleClientset(&v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "proxy-ca", Namespace: proxyTrustedCANamespace},
+			Data:       map[string]string{proxyTrustedCAKey: string(caPEM)},
+		})
+	})
+
+	AfterEach(func() {
+		setClusterProxy(nil)
+	})
+
+	It("should route requests through the proxy of the status unless excluded by noProxy", func() {
+		reconciler.configClient = configclient.NewSimpleClientset(newProxy(
+			configv1.ProxySpec{HTTPSProxy: "http://spec-proxy:3128", TrustedCA: configv1.ConfigMapNameReference{Name: "proxy-ca"}},
+			configv1.ProxyStatus{HTTPSProxy: "http://proxy:3128", NoProxy: ".svc,.cluster.local,git.internal"},
+		))
+		Expect(reconciler.reconcileClusterProxy(context.Background())).To(Succeed())
+
+		Expect(proxyFor("https://github.com/user/repo")).To(Equal("http://proxy:3128"))
+		Expect(proxyFor("https://git.internal/user/repo")).To(BeEmpty())
+		Expect(proxyFor("https://gitea.vp-gitea.svc/user/repo")).To(BeEmpty())
+		Expect(proxyTrustedCA()).To(Equal(caPEM))
+	})
+
+	It("should fall back to the spec until the status is filled in", func() {
+		reconciler.configClient = configclient.NewSimpleClientset(newProxy(
+			configv1.ProxySpec{HTTPProxy: "http://spec-proxy:3128", HTTPSProxy: "http://spec-proxy:3128"},
+			configv1.ProxyStatus{},
+		))
+		Expect(reconciler.reconcileClusterProxy(context.Background())).To(Succeed())
+		Expect(proxyFor("https://github.com/user/repo")).To(Equal("http://spec-proxy:3128"))
+		Expect(proxyTrustedCA()).To(BeNil())
+	})
+
+	It("should drop the proxy once it is removed from the cluster", func() {
+		setClusterProxy(newClusterProxy("", "http://proxy:3128", "", caPEM))
+		Expect(reconciler.reconcileClusterProxy(context.Background())).To(Succeed())
+		Expect(getClusterProxy()).To(BeNil())
+
+		reconciler.configClient = configclient.NewSimpleClientset(newProxy(configv1.ProxySpec{}, configv1.ProxyStatus{}))
+		setClusterProxy(newClusterProxy("", "http://proxy:3128", "", caPEM))
+		Expect(reconciler.reconcileClusterProxy(context.Background())).To(Succeed())
+		Expect(getClusterProxy()).To(BeNil())
+	})
+
+	It("should trust the CA of the proxy in the outbound transports", func() {
+		caCert, caPEM, _ := newTestCA()
+		setClusterProxy(newClusterProxy("", "http://proxy:3128", "", caPEM))
+		transport := getHTTPSTransport(nil)
+		Expect(transport.TLSClientConfig.RootCAs.Subjects()).To(ContainElement(caCert.RawSubject)) //nolint:staticcheck
+	})
+
+	It("should enqueue every pattern when the cluster proxy changes", func() {
+		p1 := buildPatternManifest()
+		p2 := buildPatternManifest()
+		p2.Name, p2.Namespace = "other", "elsewhere"
+		reconciler = newFakeReconciler(p1, p2)
+		Expect(isClusterProxy(newProxy(configv1.ProxySpec{}, configv1.ProxyStatus{}))).To(BeTrue())
+		Expect(reconciler.enqueueAllPatterns(context.Background(), nil)).To(HaveLen(2))
+	})
+})
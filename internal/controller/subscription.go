@@ -32,13 +32,7 @@ import (
 func newSubscription(patternsOperatorConfig PatternsOperatorConfig, disableDefaultInstance bool) *operatorv1alpha1.Subscription {
 	var newSubscription *operatorv1alpha1.Subscription
 
-	var installPlanApproval operatorv1alpha1.Approval
-
-	if patternsOperatorConfig.getStringValue(configKeyApprovalPlan) == "Manual" {
-		installPlanApproval = operatorv1alpha1.ApprovalManual
-	} else {
-		installPlanApproval = operatorv1alpha1.ApprovalAutomatic
-	}
+	installPlanApproval := subscriptionApproval(patternsOperatorConfig)
 
 	spec := &operatorv1alpha1.SubscriptionSpec{
 		CatalogSource:          patternsOperatorConfig.getStringValue(configKeyCatalogSource),
@@ -64,6 +58,35 @@ func newSubscription(patternsOperatorConfig PatternsOperatorConfig, disableDefau
 	return newSubscription
 }
 
+// newArgoCDOperatorSubscription returns the Subscription of the upstream argocd-operator. The cluster-scoped
+// instance it manages is the same ArgoCD CR as with OpenShift GitOps
+func newArgoCDOperatorSubscription(patternsOperatorConfig PatternsOperatorConfig) *operatorv1alpha1.Subscription {
+	return &operatorv1alpha1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ArgoCDOperatorPackageName,
+			Namespace: patternsOperatorConfig.getStringValue(configKeyArgoCDOperatorNamespace),
+		},
+		Spec: &operatorv1alpha1.SubscriptionSpec{
+			CatalogSource:          patternsOperatorConfig.getStringValue(configKeyArgoCDOperatorCatalogSource),
+			CatalogSourceNamespace: patternsOperatorConfig.getStringValue(configKeyArgoCDOperatorSourceNamespace),
+			Package:                ArgoCDOperatorPackageName,
+			Channel:                patternsOperatorConfig.getStringValue(configKeyArgoCDOperatorChannel),
+			InstallPlanApproval:    subscriptionApproval(patternsOperatorConfig),
+			Config: &operatorv1alpha1.SubscriptionConfig{
+				// DISABLE_DEFAULT_ARGOCD_INSTANCE is specific to OpenShift GitOps
+				Env: newSubscriptionEnvVars(false),
+			},
+		},
+	}
+}
+
+func subscriptionApproval(patternsOperatorConfig PatternsOperatorConfig) operatorv1alpha1.Approval {
+	if patternsOperatorConfig.getStringValue(configKeyApprovalPlan) == "Manual" {
+		return operatorv1alpha1.ApprovalManual
+	}
+	return operatorv1alpha1.ApprovalAutomatic
+}
+
 // newSubscriptionEnvVars returns the environment variables for the GitOps operator subscription.
 // When disableDefaultInstance is true, the DISABLE_DEFAULT_ARGOCD_INSTANCE env var is added
 // to prevent the gitops-operator from creating (and actively deleting) the default ArgoCD
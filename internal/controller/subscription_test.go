@@ -136,6 +136,35 @@ var _ = Describe("Subscription Functions", func() {
 	})
 })
 
+var _ = Describe("newArgoCDOperatorSubscription", func() {
+	It("should subscribe to the upstream catalog by default", func() {
+		sub := newArgoCDOperatorSubscription(PatternsOperatorConfig{})
+		Expect(sub.Name).To(Equal(ArgoCDOperatorPackageName))
+		Expect(sub.Namespace).To(Equal(ArgoCDOperatorDefaultSubscriptionNamespace))
+		Expect(sub.Spec.Package).To(Equal(ArgoCDOperatorPackageName))
+		Expect(sub.Spec.CatalogSource).To(Equal(ArgoCDOperatorDefaultCatalogSource))
+		Expect(sub.Spec.CatalogSourceNamespace).To(Equal(ArgoCDOperatorDefaultCatalogSourceNamespace))
+		Expect(sub.Spec.Channel).To(Equal(ArgoCDOperatorDefaultChannel))
+		Expect(sub.Spec.InstallPlanApproval).To(Equal(operatorv1alpha1.ApprovalAutomatic))
+		Expect(sub.Spec.Config.Env).To(Equal(newSubscriptionEnvVars(false)))
+	})
+
+	It("should use the configured catalog", func() {
+		sub := newArgoCDOperatorSubscription(PatternsOperatorConfig{
+			configKeyArgoCDOperatorCatalogSource:   "my-catalog",
+			configKeyArgoCDOperatorSourceNamespace: "my-marketplace",
+			configKeyArgoCDOperatorChannel:         "stable",
+			configKeyArgoCDOperatorNamespace:       "argocd-system",
+			configKeyApprovalPlan:                  "Manual",
+		})
+		Expect(sub.Namespace).To(Equal("argocd-system"))
+		Expect(sub.Spec.CatalogSource).To(Equal("my-catalog"))
+		Expect(sub.Spec.CatalogSourceNamespace).To(Equal("my-marketplace"))
+		Expect(sub.Spec.Channel).To(Equal("stable"))
+		Expect(sub.Spec.InstallPlanApproval).To(Equal(operatorv1alpha1.ApprovalManual))
+	})
+})
+
 var _ = Describe("UpdateSubscription", func() {
 	var (
 		client         *olmclient.Clientset
@@ -27,6 +27,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -39,6 +40,7 @@ import (
 	api "github.com/hybrid-cloud-patterns/patterns-operator/api/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 
@@ -61,7 +63,7 @@ func logOnce(message string) {
 }
 
 // getPatternConditionByStatus returns a copy of the pattern condition defined by the status and the index in the slice if it exists, otherwise -1 and nil
-func getPatternConditionByStatus(conditions []api.PatternCondition, conditionStatus corev1.ConditionStatus) (int, *api.PatternCondition) {
+func getPatternConditionByStatus(conditions []metav1.Condition, conditionStatus metav1.ConditionStatus) (int, *metav1.Condition) {
 	if conditions == nil {
 		return -1, nil
 	}
@@ -74,56 +76,41 @@ func getPatternConditionByStatus(conditions []api.PatternCondition, conditionSta
 }
 
 // getPatternConditionByType returns a copy of the pattern condition defined by the type and the index in the slice if it exists, otherwise -1 and nil
-func getPatternConditionByType(conditions []api.PatternCondition, conditionType api.PatternConditionType) (int, *api.PatternCondition) {
+func getPatternConditionByType(conditions []metav1.Condition, conditionType api.PatternConditionType) (int, *metav1.Condition) {
 	if conditions == nil {
 		return -1, nil
 	}
 	for i := range conditions {
-		if conditions[i].Type == conditionType {
+		if conditions[i].Type == string(conditionType) {
 			return i, &conditions[i]
 		}
 	}
 	return -1, nil
 }
 
-// setPatternCondition sets a condition on the pattern status
-func setPatternCondition(pattern *api.Pattern, conditionType api.PatternConditionType, status corev1.ConditionStatus, message string) {
-	now := metav1.Now()
-
-	// Find existing condition
-	idx, existingCondition := getPatternConditionByType(pattern.Status.Conditions, conditionType)
-
-	newCondition := api.PatternCondition{
-		Type:           conditionType,
-		Status:         status,
-		LastUpdateTime: now,
-		Message:        message,
-	}
-
-	// Set transition time if status is changing
-	if existingCondition == nil || existingCondition.Status != status {
-		newCondition.LastTransitionTime = now
-	} else if existingCondition != nil {
-		// Preserve the transition time if status hasn't changed
-		newCondition.LastTransitionTime = existingCondition.LastTransitionTime
-	}
+// setPatternCondition sets a condition on the pattern status for the current generation of the pattern.
+// The transition time only changes with the status. Returns true if the condition changed
+func setPatternCondition(pattern *api.Pattern, conditionType api.PatternConditionType, status metav1.ConditionStatus, reason, message string) bool {
+	return meta.SetStatusCondition(&pattern.Status.Conditions, metav1.Condition{
+		Type:               string(conditionType),
+		Status:             status,
+		ObservedGeneration: pattern.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
 
-	// Add or update the condition
-	if idx == -1 {
-		// Add new condition
-		pattern.Status.Conditions = append(pattern.Status.Conditions, newCondition)
-	} else {
-		// Update existing condition
-		pattern.Status.Conditions[idx] = newCondition
-	}
+// removePatternCondition removes a condition from the pattern status. Returns true if it was present
+func removePatternCondition(pattern *api.Pattern, conditionType api.PatternConditionType) bool {
+	return meta.RemoveStatusCondition(&pattern.Status.Conditions, string(conditionType))
 }
 
-// removePatternCondition removes a condition from the pattern status
-func removePatternCondition(pattern *api.Pattern, conditionType api.PatternConditionType) { //nolint:unparam
-	idx, _ := getPatternConditionByType(pattern.Status.Conditions, conditionType)
-	if idx != -1 {
-		pattern.Status.Conditions = append(pattern.Status.Conditions[:idx], pattern.Status.Conditions[idx+1:]...)
-	}
+// dropLegacyConditions removes the conditions written by operator versions that did not set a reason.
+// They would fail the validation of the status update and are recomputed by the reconcile loop
+func dropLegacyConditions(pattern *api.Pattern) {
+	pattern.Status.Conditions = slices.DeleteFunc(pattern.Status.Conditions, func(c metav1.Condition) bool {
+		return c.Reason == "" || c.LastTransitionTime.IsZero()
+	})
 }
 
 // status:
@@ -429,7 +416,7 @@ func getHTTPSTransport(fullClient kubernetes.Interface) *nethttp.Transport {
 		TLSClientConfig: &tls.Config{
 			MinVersion: tls.VersionTLS12,
 		},
-		Proxy: nethttp.ProxyFromEnvironment,
+		Proxy: proxyForRequest,
 	}
 	var cacerts bytes.Buffer
 	if kuberoot != "" {
@@ -453,6 +440,10 @@ func getHTTPSTransport(fullClient kubernetes.Interface) *nethttp.Transport {
 	if certErr != nil {
 		return myTransport
 	}
+	// A TLS intercepting cluster proxy presents certificates signed by its own CA
+	if proxyCA := proxyTrustedCA(); proxyCA != nil {
+		caCertPool.AppendCertsFromPEM(proxyCA)
+	}
 	myTransport.TLSClientConfig.RootCAs = caCertPool
 	return myTransport
 }
@@ -330,18 +330,18 @@ var _ = Describe("hasExperimentalCapability", func() {
 
 var _ = Describe("GetPatternConditionByStatus", func() {
 	var (
-		conditions      []api.PatternCondition
-		conditionStatus corev1.ConditionStatus
+		conditions      []metav1.Condition
+		conditionStatus metav1.ConditionStatus
 		expectedIndex   int
-		expectedResult  *api.PatternCondition
+		expectedResult  *metav1.Condition
 	)
 
 	BeforeEach(func() {
-		conditions = []api.PatternCondition{
-			{Type: "ConditionA", Status: corev1.ConditionFalse},
-			{Type: "ConditionB", Status: corev1.ConditionTrue},
+		conditions = []metav1.Condition{
+			{Type: "ConditionA", Status: metav1.ConditionFalse},
+			{Type: "ConditionB", Status: metav1.ConditionTrue},
 		}
-		conditionStatus = corev1.ConditionTrue
+		conditionStatus = metav1.ConditionTrue
 	})
 
 	Context("when conditions are nil", func() {
@@ -360,7 +360,7 @@ var _ = Describe("GetPatternConditionByStatus", func() {
 
 	Context("when conditions are empty", func() {
 		BeforeEach(func() {
-			conditions = []api.PatternCondition{}
+			conditions = []metav1.Condition{}
 		})
 
 		It("should return -1 and nil", func() {
@@ -373,7 +373,7 @@ var _ = Describe("GetPatternConditionByStatus", func() {
 	Context("when condition is found", func() {
 		BeforeEach(func() {
 			expectedIndex = 1
-			expectedResult = &api.PatternCondition{Type: "ConditionB", Status: corev1.ConditionTrue}
+			expectedResult = &metav1.Condition{Type: "ConditionB", Status: metav1.ConditionTrue}
 		})
 
 		It("should return the index and the condition", func() {
@@ -387,7 +387,7 @@ var _ = Describe("GetPatternConditionByStatus", func() {
 		BeforeEach(func() {
 			expectedIndex = -1
 			expectedResult = nil
-			conditions[1].Status = corev1.ConditionFalse // Modify to ensure condition is not found
+			conditions[1].Status = metav1.ConditionFalse // Modify to ensure condition is not found
 		})
 
 		It("should return -1 and nil", func() {
@@ -400,10 +400,10 @@ var _ = Describe("GetPatternConditionByStatus", func() {
 
 var _ = Describe("GetPatternConditionByType", func() {
 	var (
-		conditions     []api.PatternCondition
+		conditions     []metav1.Condition
 		conditionType  api.PatternConditionType
 		expectedIndex  int
-		expectedResult *api.PatternCondition
+		expectedResult *metav1.Condition
 	)
 
 	JustBeforeEach(func() {
@@ -427,7 +427,7 @@ var _ = Describe("GetPatternConditionByType", func() {
 
 	Context("when conditions are empty", func() {
 		BeforeEach(func() {
-			conditions = []api.PatternCondition{}
+			conditions = []metav1.Condition{}
 			conditionType = "ConditionType1"
 		})
 
@@ -440,16 +440,16 @@ var _ = Describe("GetPatternConditionByType", func() {
 
 	Context("when condition is found", func() {
 		BeforeEach(func() {
-			conditions = []api.PatternCondition{
-				{Type: "ConditionType1", Status: corev1.ConditionFalse},
-				{Type: "ConditionType2", Status: corev1.ConditionTrue},
+			conditions = []metav1.Condition{
+				{Type: "ConditionType1", Status: metav1.ConditionFalse},
+				{Type: "ConditionType2", Status: metav1.ConditionTrue},
 			}
 			conditionType = "ConditionType2"
 
 		})
 		JustBeforeEach(func() {
 			expectedIndex = 1
-			expectedResult = &api.PatternCondition{Type: "ConditionType2", Status: corev1.ConditionTrue}
+			expectedResult = &metav1.Condition{Type: "ConditionType2", Status: metav1.ConditionTrue}
 		})
 		It("should return the index and the condition", func() {
 			index, result := getPatternConditionByType(conditions, conditionType)
@@ -460,9 +460,9 @@ var _ = Describe("GetPatternConditionByType", func() {
 
 	Context("when condition is not found", func() {
 		BeforeEach(func() {
-			conditions = []api.PatternCondition{
-				{Type: "ConditionType1", Status: corev1.ConditionFalse},
-				{Type: "ConditionType3", Status: corev1.ConditionTrue},
+			conditions = []metav1.Condition{
+				{Type: "ConditionType1", Status: metav1.ConditionFalse},
+				{Type: "ConditionType3", Status: metav1.ConditionTrue},
 			}
 			conditionType = "ConditionType2"
 		})
@@ -1731,17 +1731,17 @@ var _ = Describe("getClusterWideArgoNamespace defaults", func() {
 })
 
 var _ = Describe("Pattern condition search functions", func() {
-	var conditions []api.PatternCondition
+	var conditions []metav1.Condition
 
 	BeforeEach(func() {
-		conditions = []api.PatternCondition{
+		conditions = []metav1.Condition{
 			{
-				Type:   api.Synced,
-				Status: corev1.ConditionTrue,
+				Type:   string(api.Synced),
+				Status: metav1.ConditionTrue,
 			},
 			{
-				Type:   api.Degraded,
-				Status: corev1.ConditionFalse,
+				Type:   string(api.Degraded),
+				Status: metav1.ConditionFalse,
 			},
 		}
 	})
@@ -1749,7 +1749,7 @@ var _ = Describe("Pattern condition search functions", func() {
 	Describe("getPatternConditionByStatus", func() {
 		Context("when conditions is nil", func() {
 			It("should return -1 and nil", func() {
-				idx, cond := getPatternConditionByStatus(nil, corev1.ConditionTrue)
+				idx, cond := getPatternConditionByStatus(nil, metav1.ConditionTrue)
 				Expect(idx).To(Equal(-1))
 				Expect(cond).To(BeNil())
 			})
@@ -1757,16 +1757,16 @@ var _ = Describe("Pattern condition search functions", func() {
 
 		Context("when condition exists", func() {
 			It("should return the index and condition", func() {
-				idx, cond := getPatternConditionByStatus(conditions, corev1.ConditionTrue)
+				idx, cond := getPatternConditionByStatus(conditions, metav1.ConditionTrue)
 				Expect(idx).To(Equal(0))
 				Expect(cond).ToNot(BeNil())
-				Expect(cond.Type).To(Equal(api.Synced))
+				Expect(cond.Type).To(Equal(string(api.Synced)))
 			})
 		})
 
 		Context("when condition does not exist", func() {
 			It("should return -1 and nil", func() {
-				idx, cond := getPatternConditionByStatus(conditions, corev1.ConditionUnknown)
+				idx, cond := getPatternConditionByStatus(conditions, metav1.ConditionUnknown)
 				Expect(idx).To(Equal(-1))
 				Expect(cond).To(BeNil())
 			})
@@ -1787,7 +1787,7 @@ var _ = Describe("Pattern condition search functions", func() {
 				idx, cond := getPatternConditionByType(conditions, api.Degraded)
 				Expect(idx).To(Equal(1))
 				Expect(cond).ToNot(BeNil())
-				Expect(cond.Status).To(Equal(corev1.ConditionFalse))
+				Expect(cond.Status).To(Equal(metav1.ConditionFalse))
 			})
 		})
 
@@ -1889,74 +1889,87 @@ var _ = Describe("newSecret", func() {
 })
 
 var _ = Describe("DropLocalGitPaths", func() {
-	It("should remove the vp temp folder", func() {
-		td := filepath.Join(os.TempDir(), VPTmpFolder, "test-drop")
+	It("should remove the folder of the pattern", func() {
+		td := filepath.Join(getPatternGitPath("test-ns", "test-drop"), "repo")
 		err := os.MkdirAll(td, 0755)
 		Expect(err).ToNot(HaveOccurred())
 
-		err = DropLocalGitPaths()
+		err = DropLocalGitPaths("test-ns", "test-drop")
 		Expect(err).ToNot(HaveOccurred())
 
 		_, err = os.Stat(td)
 		Expect(os.IsNotExist(err)).To(BeTrue())
 	})
 
+	It("should not remove the folders of other patterns", func() {
+		other := filepath.Join(getPatternGitPath("test-ns", "test-keep"), "repo")
+		err := os.MkdirAll(other, 0755)
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(getPatternGitPath("test-ns", "test-keep"))
+
+		err = DropLocalGitPaths("test-ns", "test-drop")
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = os.Stat(other)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
 	It("should not error if folder does not exist", func() {
-		err := DropLocalGitPaths()
+		err := DropLocalGitPaths("test-ns", "test-missing")
 		Expect(err).ToNot(HaveOccurred())
 	})
 })
 
 var _ = Describe("getPatternConditionByStatus", func() {
-	var conditions []api.PatternCondition
+	var conditions []metav1.Condition
 
 	BeforeEach(func() {
-		conditions = []api.PatternCondition{
+		conditions = []metav1.Condition{
 			{
-				Type:   api.GitInSync,
-				Status: corev1.ConditionTrue,
+				Type:   string(api.GitInSync),
+				Status: metav1.ConditionTrue,
 			},
 			{
-				Type:   api.Degraded,
-				Status: corev1.ConditionFalse,
+				Type:   string(api.Degraded),
+				Status: metav1.ConditionFalse,
 			},
 			{
-				Type:   api.Progressing,
-				Status: corev1.ConditionUnknown,
+				Type:   string(api.Progressing),
+				Status: metav1.ConditionUnknown,
 			},
 		}
 	})
 
 	Context("when condition with given status exists", func() {
 		It("should return the index and the condition for ConditionTrue", func() {
-			idx, cond := getPatternConditionByStatus(conditions, corev1.ConditionTrue)
+			idx, cond := getPatternConditionByStatus(conditions, metav1.ConditionTrue)
 			Expect(idx).To(Equal(0))
 			Expect(cond).ToNot(BeNil())
-			Expect(cond.Type).To(Equal(api.GitInSync))
+			Expect(cond.Type).To(Equal(string(api.GitInSync)))
 		})
 
 		It("should return the index and the condition for ConditionFalse", func() {
-			idx, cond := getPatternConditionByStatus(conditions, corev1.ConditionFalse)
+			idx, cond := getPatternConditionByStatus(conditions, metav1.ConditionFalse)
 			Expect(idx).To(Equal(1))
 			Expect(cond).ToNot(BeNil())
-			Expect(cond.Type).To(Equal(api.Degraded))
+			Expect(cond.Type).To(Equal(string(api.Degraded)))
 		})
 
 		It("should return the index and the condition for ConditionUnknown", func() {
-			idx, cond := getPatternConditionByStatus(conditions, corev1.ConditionUnknown)
+			idx, cond := getPatternConditionByStatus(conditions, metav1.ConditionUnknown)
 			Expect(idx).To(Equal(2))
 			Expect(cond).ToNot(BeNil())
-			Expect(cond.Type).To(Equal(api.Progressing))
+			Expect(cond.Type).To(Equal(string(api.Progressing)))
 		})
 	})
 
 	Context("when condition with given status does not exist", func() {
 		It("should return -1 and nil", func() {
 			// All statuses are accounted for, so create a new slice with only one status
-			limited := []api.PatternCondition{
-				{Type: api.GitInSync, Status: corev1.ConditionTrue},
+			limited := []metav1.Condition{
+				{Type: string(api.GitInSync), Status: metav1.ConditionTrue},
 			}
-			idx, cond := getPatternConditionByStatus(limited, corev1.ConditionFalse)
+			idx, cond := getPatternConditionByStatus(limited, metav1.ConditionFalse)
 			Expect(idx).To(Equal(-1))
 			Expect(cond).To(BeNil())
 		})
@@ -1964,7 +1977,7 @@ var _ = Describe("getPatternConditionByStatus", func() {
 
 	Context("when conditions slice is nil", func() {
 		It("should return -1 and nil", func() {
-			idx, cond := getPatternConditionByStatus(nil, corev1.ConditionTrue)
+			idx, cond := getPatternConditionByStatus(nil, metav1.ConditionTrue)
 			Expect(idx).To(Equal(-1))
 			Expect(cond).To(BeNil())
 		})
@@ -1972,7 +1985,7 @@ var _ = Describe("getPatternConditionByStatus", func() {
 
 	Context("when conditions slice is empty", func() {
 		It("should return -1 and nil", func() {
-			idx, cond := getPatternConditionByStatus([]api.PatternCondition{}, corev1.ConditionTrue)
+			idx, cond := getPatternConditionByStatus([]metav1.Condition{}, metav1.ConditionTrue)
 			Expect(idx).To(Equal(-1))
 			Expect(cond).To(BeNil())
 		})
@@ -1980,36 +1993,36 @@ var _ = Describe("getPatternConditionByStatus", func() {
 
 	Context("when multiple conditions have the same status", func() {
 		It("should return the first matching index", func() {
-			dupes := []api.PatternCondition{
-				{Type: api.GitInSync, Status: corev1.ConditionTrue},
-				{Type: api.Synced, Status: corev1.ConditionTrue},
+			dupes := []metav1.Condition{
+				{Type: string(api.GitInSync), Status: metav1.ConditionTrue},
+				{Type: string(api.Synced), Status: metav1.ConditionTrue},
 			}
-			idx, cond := getPatternConditionByStatus(dupes, corev1.ConditionTrue)
+			idx, cond := getPatternConditionByStatus(dupes, metav1.ConditionTrue)
 			Expect(idx).To(Equal(0))
 			Expect(cond).ToNot(BeNil())
-			Expect(cond.Type).To(Equal(api.GitInSync))
+			Expect(cond.Type).To(Equal(string(api.GitInSync)))
 		})
 	})
 })
 
 var _ = Describe("getPatternConditionByType", func() {
-	var conditions []api.PatternCondition
+	var conditions []metav1.Condition
 
 	BeforeEach(func() {
-		conditions = []api.PatternCondition{
+		conditions = []metav1.Condition{
 			{
-				Type:    api.GitInSync,
-				Status:  corev1.ConditionTrue,
+				Type:    string(api.GitInSync),
+				Status:  metav1.ConditionTrue,
 				Message: "in sync",
 			},
 			{
-				Type:    api.Degraded,
-				Status:  corev1.ConditionFalse,
+				Type:    string(api.Degraded),
+				Status:  metav1.ConditionFalse,
 				Message: "not degraded",
 			},
 			{
-				Type:    api.Progressing,
-				Status:  corev1.ConditionTrue,
+				Type:    string(api.Progressing),
+				Status:  metav1.ConditionTrue,
 				Message: "progressing",
 			},
 		}
@@ -2027,7 +2040,7 @@ var _ = Describe("getPatternConditionByType", func() {
 			idx, cond := getPatternConditionByType(conditions, api.Degraded)
 			Expect(idx).To(Equal(1))
 			Expect(cond).ToNot(BeNil())
-			Expect(cond.Status).To(Equal(corev1.ConditionFalse))
+			Expect(cond.Status).To(Equal(metav1.ConditionFalse))
 		})
 
 		It("should return the index and the condition for Progressing", func() {
@@ -2062,7 +2075,7 @@ var _ = Describe("getPatternConditionByType", func() {
 
 	Context("when conditions slice is empty", func() {
 		It("should return -1 and nil", func() {
-			idx, cond := getPatternConditionByType([]api.PatternCondition{}, api.GitInSync)
+			idx, cond := getPatternConditionByType([]metav1.Condition{}, api.GitInSync)
 			Expect(idx).To(Equal(-1))
 			Expect(cond).To(BeNil())
 		})
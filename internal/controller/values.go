@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 
 	"helm.sh/helm/v3/pkg/chart"
 	"helm.sh/helm/v3/pkg/chartutil"
@@ -52,6 +53,31 @@ func getClusterGroupValue(key string, values map[string]any) any {
 	return nil
 }
 
+// getClusterGroupNamespaces returns the sorted names of the namespaces listed in clusterGroup.namespaces.
+// Entries can be plain names or single-key maps holding the settings of the namespace
+func getClusterGroupNamespaces(values map[string]any) []string {
+	var namespaces []string
+	switch v := getClusterGroupValue("namespaces", values).(type) {
+	case []any:
+		for _, entry := range v {
+			switch e := entry.(type) {
+			case string:
+				namespaces = append(namespaces, e)
+			case map[string]any:
+				for name := range e {
+					namespaces = append(namespaces, name)
+				}
+			}
+		}
+	case map[string]any:
+		for name := range v {
+			namespaces = append(namespaces, name)
+		}
+	}
+	slices.Sort(namespaces)
+	return slices.Compact(namespaces)
+}
+
 func helmTpl(templateString string, valueFiles []string, values map[string]any) (string, error) {
 	// Create a fake chart with the template.
 	fakeChart := &chart.Chart{
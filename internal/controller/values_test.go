@@ -84,6 +84,30 @@ var _ = Describe("Helm Values", func() {
 	})
 })
 
+var _ = Describe("getClusterGroupNamespaces", func() {
+	It("should return nothing when clusterGroup.namespaces is not set", func() {
+		Expect(getClusterGroupNamespaces(map[string]any{})).To(BeEmpty())
+	})
+
+	It("should list plain names and the keys of namespaces with settings, sorted", func() {
+		values := map[string]any{"clusterGroup": map[string]any{"namespaces": []any{
+			"vault",
+			map[string]any{"golang-external-secrets": map[string]any{"labels": map[string]any{"foo": "bar"}}},
+			"open-cluster-management",
+			"vault",
+		}}}
+		Expect(getClusterGroupNamespaces(values)).To(Equal([]string{"golang-external-secrets", "open-cluster-management", "vault"}))
+	})
+
+	It("should support namespaces given as a map", func() {
+		values := map[string]any{"clusterGroup": map[string]any{"namespaces": map[string]any{
+			"vault":                   map[string]any{},
+			"open-cluster-management": nil,
+		}}}
+		Expect(getClusterGroupNamespaces(values)).To(Equal([]string{"open-cluster-management", "vault"}))
+	})
+})
+
 var _ = Describe("CountApplicationsAndSets", func() {
 	var (
 		input map[string]any